@@ -10,12 +10,33 @@ type Document struct {
 
 	Blocks []Block
 
+	Footnotes []Footnote
+
+	Location Location
+}
+
+// Footnote of the document, footnotes are numbered in the order of appearance
+type Footnote struct {
+	Id      string
+	Number  int
+	Inlines Inlines
+
 	Location Location
 }
 
 type Header struct {
-	Title   Inlines
-	Authors []Author
+	Title            Inlines
+	Authors          []Author
+	AttributeEntries []AttributeEntry
+
+	Location Location
+}
+
+// Attribute entry ":name: value" of the header, the value may take several lines
+type AttributeEntry struct {
+	Name  string
+	Value string
+	Unset bool // ":name!:" or ":!name:"
 
 	Location Location
 }
@@ -47,6 +68,7 @@ type Block interface {
 	block()
 }
 
+func (b *Section) block()         {}
 func (b *List) block()            {}
 func (b *DescriptionList) block() {}
 func (b *DiscreteHeading) block() {}
@@ -54,6 +76,7 @@ func (b *Break) block()           {}
 func (b *BlockMacro) block()      {}
 func (b *LeafBlock) block()       {}
 func (b *ParentBlock) block()     {}
+func (b *Table) block()           {}
 
 type Section struct {
 	Name   Name // SectionName
@@ -74,6 +97,7 @@ type List struct {
 type DescriptionList struct {
 	Name   Name // DListName
 	Marker string
+	Items  []DescriptionListItem
 
 	AbstructBlock
 }
@@ -118,10 +142,18 @@ type LeafBlock struct {
 	Form      Form
 	Inlines   []Inlines
 	Delimiter string
+	Callouts  []Callout // listing and literal blocks only
 
 	AbstructBlock
 }
 
+// Callout marker like "<1>" at the end of the verbatim line
+type Callout struct {
+	Number int
+
+	Location Location
+}
+
 // if "name"="admonition" then Variant required
 type ParentBlock struct {
 	Name      Name
@@ -133,6 +165,47 @@ type ParentBlock struct {
 	AbstructBlock
 }
 
+// Rows of the header and the footer are kept apart from the body rows
+type Table struct {
+	Name      Name    // TableName
+	Form      Form    // DelimitedForm
+	Variant   Variant // PSVVariant, CSVVariant or DSVVariant
+	Delimiter string
+	Columns   []TableColumn
+	Head      []TableRow
+	Body      []TableRow
+	Foot      []TableRow
+
+	AbstructBlock
+}
+
+// Column specification from the "cols" attribute
+type TableColumn struct {
+	Width  int // relative width, 0 for the automatic width "~"
+	HAlign Align
+	VAlign Align
+	Style  Style
+}
+
+type TableRow struct {
+	Cells []TableCell
+
+	Location Location
+}
+
+// Cell spans and alignment default to the ones of its column
+type TableCell struct {
+	ColSpan int
+	RowSpan int
+	HAlign  Align
+	VAlign  Align
+	Style   Style
+	Inlines []Inlines // all styles except AsciiDocStyle
+	Blocks  []Block   // AsciiDocStyle only
+
+	Location Location
+}
+
 type BlockMetaData struct {
 	Attributes map[string]string // key pattern ^(?:[a-zA-Z_][a-zA-Z0-9_-]*|\\$[1-9][0-9]*)$
 	Options    []string
@@ -150,6 +223,7 @@ type Inline interface {
 func (i *InlineSpan) inline()    {}
 func (i *InlineRef) inline()     {}
 func (i *InlineLiteral) inline() {}
+func (i *InlineMacro) inline()   {}
 
 type AbstractParentInline struct {
 	Type    Type
@@ -174,6 +248,17 @@ type InlineRef struct {
 	AbstractParentInline
 }
 
+// Inline macro like "kbd:[Ctrl+C]"
+type InlineMacro struct {
+	Name       Name // ImageName, KbdName, BtnName, MenuName, FootnoteName or StemName
+	Target     string
+	Items      []string          // keys of "kbd", submenus and the item of "menu"
+	Attributes map[string]string // attributes of "image"
+	Number     int               // number of "footnote"
+
+	AbstractParentInline
+}
+
 type InlineLiteral struct {
 	Name  Name
 	Type  Type
@@ -196,4 +281,6 @@ type Location []LocationBoundary
 type LocationBoundary struct {
 	Line    int `json:"line"`
 	Collumn int `json:"col"`
+	// Included file of the line, empty for the document itself
+	File string `json:"file,omitempty"`
 }
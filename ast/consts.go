@@ -44,9 +44,16 @@ const (
 	OpenName       Name = "open"       // Parent Block Name
 	QuoteName      Name = "quote"      // Parent Block Name
 
+	TableName Name = "table" // Table Name
+
 	RefName     Name = "ref"     // Inline Ref Name
 
 	SpanName    Name = "span"    // Inline Span Name
+
+	KbdName      Name = "kbd"      // Inline Macro Name
+	BtnName      Name = "btn"      // Inline Macro Name
+	MenuName     Name = "menu"     // Inline Macro Name
+	FootnoteName Name = "footnote" // Inline Macro Name
     
 	TextName    Name = "text"    // Inline Literal Name
 	CharRefName Name = "charref" // Inline Literal Name
@@ -76,6 +83,10 @@ const (
 
 	LinkVariant Variant = "link" // Inline Ref "link" Variant
 	XRefVariant Variant = "xref" // Inline Ref "ref" Variant
+
+	PSVVariant Variant = "psv" // Table "psv" Variant
+	CSVVariant Variant = "csv" // Table "csv" Variant
+	DSVVariant Variant = "dsv" // Table "dsv" Variant
 )
 
 type Form string
@@ -90,3 +101,26 @@ const (
 	ConstrainedForm   Form = "constrained"   // Inline Span "constrained" Form
 	UnConstrainedForm Form = "unconstrained" // Inline Span "unconstrained" Form
 )
+
+type Align string
+
+const (
+	LeftAlign   Align = "left"   // Table cell horizontal "<" Align
+	CenterAlign Align = "center" // Table cell horizontal "^" Align
+	RightAlign  Align = "right"  // Table cell horizontal ">" Align
+	TopAlign    Align = "top"    // Table cell vertical ".<" Align
+	MiddleAlign Align = "middle" // Table cell vertical ".^" Align
+	BottomAlign Align = "bottom" // Table cell vertical ".>" Align
+)
+
+type Style string
+
+const (
+	DefaultStyle   Style = "default"   // Table cell "d" Style
+	AsciiDocStyle  Style = "asciidoc"  // Table cell "a" Style
+	EmphasisStyle  Style = "emphasis"  // Table cell "e" Style
+	HeaderStyle    Style = "header"    // Table cell "h" Style
+	LiteralStyle   Style = "literal"   // Table cell "l" Style
+	MonospaceStyle Style = "monospace" // Table cell "m" Style
+	StrongStyle    Style = "strong"    // Table cell "s" Style
+)
@@ -0,0 +1,293 @@
+package parser
+
+import (
+	"bytes"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+// Attribute reference "{name}" or the inline attribute entry "{set:name:value}", "{set:name!}"
+var attributeRefRx = regexp.MustCompile(`^\{(?:set:([\w][\w-]*)(!|:[^}]*)?|([\w][\w-]*))\}`)
+
+// Built-in attributes which are defined in every document
+var intrinsicAttributes = map[string]string{
+	"empty":          "",
+	"blank":          "",
+	"sp":             " ",
+	"nbsp":           "&#160;",
+	"zwsp":           "&#8203;",
+	"wj":             "&#8288;",
+	"apos":           "&#39;",
+	"quot":           "&#34;",
+	"lsquo":          "&#8216;",
+	"rsquo":          "&#8217;",
+	"ldquo":          "&#8220;",
+	"rdquo":          "&#8221;",
+	"deg":            "&#176;",
+	"plus":           "&#43;",
+	"brvbar":         "&#166;",
+	"vbar":           "|",
+	"amp":            "&",
+	"lt":             "<",
+	"gt":             ">",
+	"startsb":        "[",
+	"endsb":          "]",
+	"caret":          "^",
+	"asterisk":       "*",
+	"tilde":          "~",
+	"backslash":      `\`,
+	"backtick":       "`",
+	"two-colons":     "::",
+	"two-semicolons": ";;",
+	"cpp":            "C++",
+	"pp":             "&#43;&#43;",
+}
+
+// Attribute state at the start of the document
+func initialAttributes() map[string]string {
+	attrs := maps.Clone(intrinsicAttributes)
+
+	now := time.Now()
+	attrs["localdate"] = now.Format(time.DateOnly)
+	attrs["localtime"] = now.Format("15:04:05 -0700")
+	attrs["localyear"] = now.Format("2006")
+
+	return attrs
+}
+
+// Replacement of the attribute reference which starts at text[x]
+//
+// Inline attribute entry is applied unless the attribute is locked, and replaced with nothing.
+// Reference to the missing attribute is handled by the "attribute-missing" attribute:
+// "skip" keeps the reference, "warn" keeps it and reports, "drop" and "drop-line" drop it.
+//
+// ok is false if there is no reference at x or the reference is kept as is.
+func (p *parser) parseAttributeRef(text []byte, x, lineNum int) (value string, n int, ok bool) {
+	if text[x] != '{' {
+		return "", 0, false
+	}
+
+	m := attributeRefRx.FindSubmatch(text[x:])
+	if m == nil {
+		return "", 0, false
+	}
+
+	if m[1] != nil {
+		name := string(m[1])
+
+		switch {
+		case p.locked[name]:
+		case string(m[2]) == "!":
+			delete(p.attributes, name)
+		case m[2] == nil:
+			p.attributes[name] = ""
+		default:
+			p.attributes[name] = string(m[2][1:])
+		}
+
+		return "", len(m[0]), true
+	}
+
+	name := string(m[3])
+
+	if value, ok := p.attributes[name]; ok {
+		return value, len(m[0]), true
+	}
+
+	switch p.attributes["attribute-missing"] {
+	case "drop", "drop-line":
+		return "", len(m[0]), true
+	case "warn":
+		p.report(lineNum, "skipping reference to missing attribute: %s", name)
+	}
+
+	return "", 0, false
+}
+
+// Text with the attribute references replaced
+func (p *parser) substituteAttributes(text []byte, lineNum int) []byte {
+	var out []byte
+
+	for x := 0; x < len(text); {
+		if text[x] == '\\' && x+1 < len(text) {
+			if m := attributeRefRx.Find(text[x+1:]); m != nil {
+				out = append(out, m...)
+				x += 1 + len(m)
+				continue
+			}
+		}
+
+		if value, n, ok := p.parseAttributeRef(text, x, lineNum); ok {
+			out = append(out, value...)
+			x += n
+			continue
+		}
+
+		out = append(out, text[x])
+		x++
+	}
+
+	return out
+}
+
+// Line with the reference to the missing attribute is dropped if "attribute-missing" is "drop-line",
+// the line with "{set:name!}" is dropped if "attribute-undefined" is "drop-line" or not set.
+//
+// Attribute entries of the dropped line are applied.
+func (p *parser) dropsLine(content []byte) bool {
+	if bytes.IndexByte(content, '{') < 0 {
+		return false
+	}
+
+	drop := false
+
+	for x := 0; x < len(content); x++ {
+		if content[x] == '\\' {
+			x++
+			continue
+		}
+
+		m := attributeRefRx.FindSubmatch(content[x:])
+		if m == nil {
+			continue
+		}
+
+		switch {
+		case m[1] != nil:
+			if string(m[2]) == "!" && p.attributes["attribute-undefined"] != "drop" {
+				drop = true
+			}
+			p.parseAttributeRef(content, x, 0)
+		default:
+			_, defined := p.attributes[string(m[3])]
+			if !defined && p.attributes["attribute-missing"] == "drop-line" {
+				drop = true
+			}
+		}
+
+		x += len(m[0]) - 1
+	}
+
+	return drop
+}
+
+// Plain text of the inlines, like the document title for the "doctitle" attribute
+func inlinesText(inlines ast.Inlines) string {
+	var b strings.Builder
+
+	for _, inline := range inlines {
+		switch inline := inline.(type) {
+		case *ast.InlineLiteral:
+			b.WriteString(inline.Value)
+		case *ast.InlineSpan:
+			b.WriteString(inlinesText(inline.Inlines))
+		case *ast.InlineRef:
+			b.WriteString(inlinesText(inline.Inlines))
+		case *ast.InlineMacro:
+			b.WriteString(inlinesText(inline.Inlines))
+		}
+	}
+
+	return b.String()
+}
+
+// External attributes are applied before the document, see Options.Attributes
+func (p *parser) setExternalAttributes(attrs map[string]string) {
+	for name, value := range attrs {
+		var soft, unset bool
+
+		if n, ok := strings.CutSuffix(name, "@"); ok {
+			name, soft = n, true
+		}
+		if v, ok := strings.CutSuffix(value, "@"); ok {
+			value, soft = v, true
+		}
+		if n, ok := strings.CutSuffix(name, "!"); ok {
+			name, unset = n, true
+		}
+
+		if unset {
+			delete(p.attributes, name)
+			delete(p.external, name)
+		} else {
+			p.attributes[name] = value
+			p.external[name] = value
+		}
+
+		if !soft {
+			p.locked[name] = true
+		}
+	}
+}
+
+var attributeEntryRx = regexp.MustCompile(`^:(!?)([a-zA-Z0-9_][-a-zA-Z0-9_]*)(!?):(.*)$`)
+
+// Attribute entry sets the document attribute from this line on
+//
+// Pattern: ":name: value", ":name!:" or ":!name:" to unset the attribute
+//
+// Value ending with " \" continues on the next line joined with the space,
+// value ending with " + \" keeps the line break. Attribute references of the value are replaced.
+// Value ending with "@" is soft set, it does not override the defined attribute.
+//
+// applied is false if the entry does not change the attribute.
+func (p *parser) parseAttributeEntry(l *line) (entry ast.AttributeEntry, applied bool) {
+	start := p.lineNum
+
+	m := attributeEntryRx.FindSubmatch(l.content)
+
+	value := bytes.TrimSpace(m[4])
+
+	for bytes.HasSuffix(value, []byte(` \`)) {
+		value = bytes.TrimRight(value[:len(value)-2], " \t")
+
+		line := p.nextLine()
+		if line == nil {
+			break
+		}
+
+		if p.kind == lineEmpty {
+			p.lineNum--
+			break
+		}
+
+		sep := []byte(" ")
+		if bytes.HasSuffix(value, []byte(" +")) {
+			sep = []byte("\n")
+		}
+
+		value = append(append(slices.Clip(value), sep...), line.content...)
+	}
+
+	soft := bytes.HasSuffix(value, []byte("@"))
+	if soft {
+		value = value[:len(value)-1]
+	}
+
+	entry = ast.AttributeEntry{
+		Name:  string(m[2]),
+		Value: string(p.substituteAttributes(value, start)),
+		Unset: len(m[1]) > 0 || len(m[3]) > 0,
+		Location: []ast.LocationBoundary{
+			p.at(start, 1),
+			p.lineEnd(p.lineNum),
+		},
+	}
+
+	if _, defined := p.attributes[entry.Name]; p.locked[entry.Name] || soft && defined {
+		return entry, false
+	}
+
+	if entry.Unset {
+		delete(p.attributes, entry.Name)
+	} else {
+		p.attributes[entry.Name] = entry.Value
+	}
+
+	return entry, true
+}
@@ -0,0 +1,445 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func TestSubstituteAttributes(t *testing.T) {
+
+	const (
+		Reference   = "Reference"
+		Escaped     = "Escaped"
+		Skip        = "Missing skip"
+		Warn        = "Missing warn"
+		Drop        = "Missing drop"
+		DropLine    = "Missing drop-line"
+		InlineEntry = "Inline entry"
+		Unset       = "Inline unset"
+		Intrinsic   = "Intrinsic"
+		DocTitle    = "Document title"
+	)
+
+	cases := map[string][]string{
+		Reference: {
+			"= Title",
+			":name: World",
+			":greeting: Hello {name}",
+			"",
+			"{greeting}!",
+		},
+		Escaped: {
+			`\{name}`,
+		},
+		Skip: {
+			"{missing}",
+		},
+		Warn: {
+			"= Title",
+			":attribute-missing: warn",
+			"",
+			"a {missing}",
+		},
+		Drop: {
+			"= Title",
+			":attribute-missing: drop",
+			"",
+			"a{missing}b",
+		},
+		DropLine: {
+			"= Title",
+			":attribute-missing: drop-line",
+			"",
+			"first",
+			"{missing} second",
+			"third",
+		},
+		InlineEntry: {
+			"{set:x:1}x is {x}",
+		},
+		Unset: {
+			"{set:x:1}",
+			"{set:x!}",
+			"{x}",
+		},
+		Intrinsic: {
+			"a{sp}b{empty}c{nbsp}",
+		},
+		DocTitle: {
+			"= The *Title*",
+			"",
+			"{doctitle}",
+		},
+	}
+
+	literal := func(value string, loc ast.Location) *ast.InlineLiteral {
+		return &ast.InlineLiteral{
+			Name:     ast.TextName,
+			Type:     ast.StringType,
+			Value:    value,
+			Location: loc,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		input       []byte
+		want        []ast.Inlines
+		diagnostics []Diagnostic
+	}{
+		{
+			name:  Reference,
+			input: []byte(strings.Join(cases[Reference], "\n")),
+			want: []ast.Inlines{
+				{literal("Hello World!", location(5, 1, 5, 11))},
+			},
+		},
+		{
+			name:  Escaped,
+			input: []byte(strings.Join(cases[Escaped], "\n")),
+			want: []ast.Inlines{
+				{literal("{name}", location(1, 1, 1, 7))},
+			},
+		},
+		{
+			name:  Skip,
+			input: []byte(strings.Join(cases[Skip], "\n")),
+			want: []ast.Inlines{
+				{text("{missing}", 1, 1)},
+			},
+		},
+		{
+			name:  Warn,
+			input: []byte(strings.Join(cases[Warn], "\n")),
+			want: []ast.Inlines{
+				{text("a {missing}", 4, 1)},
+			},
+			diagnostics: []Diagnostic{
+				{
+					Line:    4,
+					Message: "skipping reference to missing attribute: missing",
+				},
+			},
+		},
+		{
+			name:  Drop,
+			input: []byte(strings.Join(cases[Drop], "\n")),
+			want: []ast.Inlines{
+				{literal("ab", location(4, 1, 4, 11))},
+			},
+		},
+		{
+			name:  DropLine,
+			input: []byte(strings.Join(cases[DropLine], "\n")),
+			want: []ast.Inlines{
+				{text("first", 4, 1)},
+				{text("third", 6, 1)},
+			},
+		},
+		{
+			name:  InlineEntry,
+			input: []byte(strings.Join(cases[InlineEntry], "\n")),
+			want: []ast.Inlines{
+				{literal("x is 1", location(1, 10, 1, 17))},
+			},
+		},
+		{
+			name:  Unset,
+			input: []byte(strings.Join(cases[Unset], "\n")),
+			want: []ast.Inlines{
+				nil,
+				{text("{x}", 3, 1)},
+			},
+		},
+		{
+			name:  Intrinsic,
+			input: []byte(strings.Join(cases[Intrinsic], "\n")),
+			want: []ast.Inlines{
+				{literal("a bc&#160;", location(1, 1, 1, 20))},
+			},
+		},
+		{
+			name:  DocTitle,
+			input: []byte(strings.Join(cases[DocTitle], "\n")),
+			want: []ast.Inlines{
+				{literal("The Title", location(3, 1, 3, 10))},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			paragraph := doc.Blocks[len(doc.Blocks)-1].(*ast.LeafBlock)
+
+			if !reflect.DeepEqual(paragraph.Inlines, tt.want) {
+				t.Errorf("parseDocument() paragraph inlines = %v, want %v", paragraph.Inlines, tt.want)
+			}
+
+			if !reflect.DeepEqual(p.diagnostics, tt.diagnostics) {
+				t.Errorf("parseDocument() diagnostics = %v, want %v", p.diagnostics, tt.diagnostics)
+			}
+		})
+	}
+}
+
+func TestHeaderAttributeValues(t *testing.T) {
+	input := strings.Join([]string{
+		"= Title",
+		":name: World",
+		":greeting: Hello {name}",
+		":today: {localdate}",
+	}, "\n")
+
+	want := map[string]string{
+		"name":     "World",
+		"greeting": "Hello World",
+		"today":    time.Now().Format(time.DateOnly),
+	}
+
+	doc := newParser([]byte(input)).parseDocument()
+
+	if !reflect.DeepEqual(doc.Attributes, want) {
+		t.Errorf("parseDocument().Attributes = %v, want %v", doc.Attributes, want)
+	}
+}
+
+func TestParseAttributeEntries(t *testing.T) {
+	input := strings.Join([]string{
+		"= Title",
+		":single-line: single line",
+		":soft-wrap: first soft wrap \\",
+		"second soft wrap \\",
+		"third soft wrap",
+		":hard-wrap: first hard wrap + \\",
+		"second hard wrap",
+		":unfinished: value \\",
+		"",
+		"Body",
+	}, "\n")
+
+	want := []ast.AttributeEntry{
+		{
+			Name:     "single-line",
+			Value:    "single line",
+			Location: location(2, 1, 2, 25),
+		},
+		{
+			Name:     "soft-wrap",
+			Value:    "first soft wrap second soft wrap third soft wrap",
+			Location: location(3, 1, 5, 15),
+		},
+		{
+			Name:     "hard-wrap",
+			Value:    "first hard wrap +\nsecond hard wrap",
+			Location: location(6, 1, 7, 16),
+		},
+		{
+			Name:     "unfinished",
+			Value:    "value",
+			Location: location(8, 1, 8, 20),
+		},
+	}
+
+	doc := newParser([]byte(input)).parseDocument()
+
+	if !reflect.DeepEqual(doc.Header.AttributeEntries, want) {
+		t.Errorf("parseDocument().Header.AttributeEntries = %v, want %v", doc.Header.AttributeEntries, want)
+	}
+
+	if len(doc.Blocks) != 1 {
+		t.Errorf("parseDocument().Blocks = %v, want the single paragraph", doc.Blocks)
+	}
+}
+
+func TestAttributeEntryStates(t *testing.T) {
+	input := strings.Join([]string{
+		"= Title",
+		":name: header",
+		":first: one",
+		":second: two",
+		":first!:",
+		":!second:",
+		":name: soft@",
+		":undefined: soft@",
+		"",
+		"{name} {undefined} {first}",
+		"",
+		":name: body",
+		"",
+		"{name}",
+	}, "\n")
+
+	wantAttributes := map[string]string{
+		"name":      "header",
+		"undefined": "soft",
+	}
+
+	literal := func(value string, loc ast.Location) *ast.InlineLiteral {
+		return &ast.InlineLiteral{
+			Name:     ast.TextName,
+			Type:     ast.StringType,
+			Value:    value,
+			Location: loc,
+		}
+	}
+
+	want := []ast.Inlines{
+		{literal("header soft {first}", location(10, 1, 10, 26))},
+		{literal("body", location(14, 1, 14, 6))},
+	}
+
+	doc := newParser([]byte(input)).parseDocument()
+
+	if !reflect.DeepEqual(doc.Attributes, wantAttributes) {
+		t.Errorf("parseDocument().Attributes = %v, want %v", doc.Attributes, wantAttributes)
+	}
+
+	if len(doc.Header.AttributeEntries) != 7 || !doc.Header.AttributeEntries[3].Unset || !doc.Header.AttributeEntries[4].Unset {
+		t.Errorf("parseDocument().Header.AttributeEntries = %v, want 7 entries with 2 unset", doc.Header.AttributeEntries)
+	}
+
+	var got []ast.Inlines
+	for _, block := range doc.Blocks {
+		got = append(got, block.(*ast.LeafBlock).Inlines...)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDocument() paragraph inlines = %v, want %v", got, want)
+	}
+}
+
+func TestExternalAttributes(t *testing.T) {
+	input := strings.Join([]string{
+		"= Title",
+		":env: doc",
+		":version: doc",
+		":revnumber: doc",
+		":draft: doc",
+		"",
+		"{set:env:inline}{env} {version} {revnumber}",
+	}, "\n")
+
+	external := map[string]string{
+		"env":        "build",
+		"version":    "1.0@",
+		"revnumber@": "2",
+		"draft!":     "",
+	}
+
+	wantAttributes := map[string]string{
+		"env":       "build",
+		"version":   "doc",
+		"revnumber": "doc",
+	}
+
+	want := []ast.Inlines{
+		{
+			&ast.InlineLiteral{
+				Name:     ast.TextName,
+				Type:     ast.StringType,
+				Value:    "build doc doc",
+				Location: location(7, 17, 7, 43),
+			},
+		},
+	}
+
+	p := newParser([]byte(input))
+	p.setExternalAttributes(external)
+
+	doc := p.parseDocument()
+
+	if !reflect.DeepEqual(doc.Attributes, wantAttributes) {
+		t.Errorf("parseDocument().Attributes = %v, want %v", doc.Attributes, wantAttributes)
+	}
+
+	paragraph := doc.Blocks[0].(*ast.LeafBlock)
+
+	if !reflect.DeepEqual(paragraph.Inlines, want) {
+		t.Errorf("parseDocument() paragraph inlines = %v, want %v", paragraph.Inlines, want)
+	}
+}
+
+func TestLockedAttributeInCell(t *testing.T) {
+	input := strings.Join([]string{
+		"|===",
+		"a|:env: cell",
+		"",
+		"{env}",
+		"|===",
+		"",
+		"{env}",
+	}, "\n")
+
+	want := []ast.Inlines{
+		{
+			&ast.InlineLiteral{
+				Name:     ast.TextName,
+				Type:     ast.StringType,
+				Value:    "build",
+				Location: location(7, 1, 7, 5),
+			},
+		},
+	}
+
+	p := newParser([]byte(input))
+	p.setExternalAttributes(map[string]string{"env": "build"})
+
+	doc := p.parseDocument()
+
+	paragraph := doc.Blocks[len(doc.Blocks)-1].(*ast.LeafBlock)
+
+	if !reflect.DeepEqual(paragraph.Inlines, want) {
+		t.Errorf("parseDocument() paragraph inlines = %v, want %v", paragraph.Inlines, want)
+	}
+}
+
+func TestAttributeInCell(t *testing.T) {
+	input := strings.Join([]string{
+		"|===",
+		"a|:env: cell",
+		"",
+		"{env}",
+		"|===",
+		"",
+		"{env}",
+	}, "\n")
+
+	text := func(value string, loc ast.Location) []ast.Inlines {
+		return []ast.Inlines{
+			{
+				&ast.InlineLiteral{
+					Name:     ast.TextName,
+					Type:     ast.StringType,
+					Value:    value,
+					Location: loc,
+				},
+			},
+		}
+	}
+
+	p := newParser([]byte(input))
+
+	doc := p.parseDocument()
+
+	table := doc.Blocks[0].(*ast.Table)
+	cell := table.Head[0].Cells[0]
+	inner := cell.Blocks[len(cell.Blocks)-1].(*ast.LeafBlock)
+
+	if want := text("cell", location(4, 1, 4, 5)); !reflect.DeepEqual(inner.Inlines, want) {
+		t.Errorf("parseDocument() cell paragraph inlines = %v, want %v", inner.Inlines, want)
+	}
+
+	paragraph := doc.Blocks[len(doc.Blocks)-1].(*ast.LeafBlock)
+
+	if want := text("{env}", location(7, 1, 7, 5)); !reflect.DeepEqual(paragraph.Inlines, want) {
+		t.Errorf("parseDocument() paragraph inlines = %v, want %v", paragraph.Inlines, want)
+	}
+}
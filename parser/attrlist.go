@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var attributeNameRx = regexp.MustCompile(`^[\w][\w-]*[ \t]*=`)
+
+// Attribute list of the block attribute line or the macro without brackets
+//
+// Pattern: "positional,name=value,'quoted, value',name=\"quoted value\""
+//
+// Positional attributes are keyed by "$1", "$2" and so on,
+// the index counts named attributes too. Returns nil for the empty list.
+func parseAttributeList(text string) map[string]string {
+	var attrs map[string]string
+
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	for index := 1; ; index++ {
+		var name, value string
+
+		text = strings.TrimLeft(text, " \t")
+
+		if m := attributeNameRx.FindStringIndex(text); m != nil {
+			name = strings.TrimSpace(text[:m[1]-1])
+			text = strings.TrimLeft(text[m[1]:], " \t")
+		}
+
+		value, text = scanAttributeValue(text)
+
+		if name != "" || value != "" {
+			if attrs == nil {
+				attrs = map[string]string{}
+			}
+
+			if name == "" {
+				name = "$" + strconv.Itoa(index)
+			}
+
+			attrs[name] = value
+		}
+
+		if text == "" {
+			break
+		}
+
+		// Skip the comma
+		text = text[1:]
+	}
+
+	return attrs
+}
+
+// Value up to the next comma, quoted value may contain commas
+//
+// Returns the value and the rest of the text starting with the comma
+func scanAttributeValue(text string) (value, rest string) {
+	if text != "" && (text[0] == '"' || text[0] == '\'') {
+		quote := text[0]
+
+		var b strings.Builder
+
+		for x := 1; x < len(text); x++ {
+			switch {
+			case text[x] == '\\' && x+1 < len(text) && text[x+1] == quote:
+				b.WriteByte(quote)
+				x++
+			case text[x] == quote:
+				rest = text[x+1:]
+				if c := strings.IndexByte(rest, ','); c >= 0 {
+					return b.String(), rest[c:]
+				}
+				return b.String(), ""
+			default:
+				b.WriteByte(text[x])
+			}
+		}
+
+		// Unclosed quote is the part of the value
+	}
+
+	if c := strings.IndexByte(text, ','); c >= 0 {
+		return strings.TrimSpace(text[:c]), text[c:]
+	}
+
+	return strings.TrimSpace(text), ""
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAttributeList(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name:  "Empty",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:  "Positional",
+			input: "source, go",
+			want: map[string]string{
+				"$1": "source",
+				"$2": "go",
+			},
+		},
+		{
+			name:  "Named",
+			input: "Alt text,width=200, height = 100",
+			want: map[string]string{
+				"$1":     "Alt text",
+				"width":  "200",
+				"height": "100",
+			},
+		},
+		{
+			name:  "Skipped positional",
+			input: ",200",
+			want: map[string]string{
+				"$2": "200",
+			},
+		},
+		{
+			name:  "Quoted",
+			input: `"Hello, world",title='It\'s here'`,
+			want: map[string]string{
+				"$1":    "Hello, world",
+				"title": "It's here",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseAttributeList(tt.input)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAttributeList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
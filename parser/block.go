@@ -0,0 +1,501 @@
+package parser
+
+import (
+	"bytes"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+// Section body contains blocks and nested sections.
+//
+// Body ends on the section title with the same or lower level
+// or at the end of the document. Title line is left unread.
+func (p *parser) parseSectionBlocks(level int) []ast.Block {
+	var blocks []ast.Block
+
+	for {
+		line := p.nextLine()
+
+		if line == nil {
+			break
+		}
+
+		switch p.kind {
+		case lineEmpty, lineComment:
+			continue
+		case lineMultilineComment:
+			p.skipCommentBlock(line)
+		case kindDocumentTitle, kindSectionTitleL1, kindSectionTitleL2,
+			kindSectionTitleL3, kindSectionTitleL4, kindSectionTitleL5:
+			// Discrete heading does not open the section
+			if p.meta != nil && isDiscreteStyle(p.meta.style) {
+				blocks = append(blocks, p.parseBlock(line))
+				continue
+			}
+
+			l := sectionLevel(p.kind)
+			if l <= level {
+				p.lineNum--
+				return blocks
+			}
+
+			blocks = append(blocks, p.parseSection(line, l))
+		default:
+			if block := p.parseBlock(line); block != nil {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+
+	return blocks
+}
+
+// Blocks inside the delimited block up to its closing delimiter
+//
+// Section titles are not allowed here and are read as paragraphs
+func (p *parser) parseBlocks() []ast.Block {
+	var blocks []ast.Block
+
+	for {
+		line := p.nextLine()
+
+		if line == nil {
+			break
+		}
+
+		switch p.kind {
+		case lineEmpty, lineComment:
+			continue
+		case lineMultilineComment:
+			p.skipCommentBlock(line)
+		default:
+			if block := p.parseBlock(line); block != nil {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+
+	return blocks
+}
+
+// Block which starts on the line l
+//
+// Returns nil if the line does not start any block
+func (p *parser) parseBlock(l *line) ast.Block {
+	switch p.kind {
+	case lineBlockAttributes:
+		p.parseBlockAttributes(l)
+		return nil
+	case lineBlockAnchor:
+		p.parseBlockAnchor(l)
+		return nil
+	case lineBlockTitle:
+		p.parseBlockTitle(l)
+		return nil
+	case lineKindAttribute:
+		// Attribute entry between the blocks changes the attributes of the next ones
+		p.parseAttributeEntry(l)
+		return nil
+	}
+
+	meta := p.takeMeta()
+
+	block := p.parseStyledBlock(l, meta)
+	if block != nil {
+		meta.apply(abstractOf(block))
+
+		// Callout list refers to the markers of the block right before it
+		if leaf, ok := block.(*ast.LeafBlock); !ok || len(leaf.Callouts) == 0 {
+			p.callouts = nil
+		}
+	}
+
+	return block
+}
+
+// Block which starts on the line l with the style from its block attribute line
+func (p *parser) parseStyledBlock(l *line, meta *blockMeta) ast.Block {
+	style := meta.style
+
+	if style == "comment" {
+		p.skipCommentStyled(l)
+		return nil
+	}
+
+	if isDiscreteStyle(style) {
+		switch p.kind {
+		case kindDocumentTitle, kindSectionTitleL1, kindSectionTitleL2,
+			kindSectionTitleL3, kindSectionTitleL4, kindSectionTitleL5:
+			return p.parseDiscreteHeading(l, sectionLevel(p.kind))
+		}
+	}
+
+	if variant, ok := admonitionVariant(style); ok {
+		switch p.kind {
+		case kindExampleDelimiter:
+			admonition := p.parseDelimitedParent(l, ast.AdmonitionName)
+			admonition.Variant = variant
+			return admonition
+		case kindText, kindAdmonition:
+			return p.parseAdmonitionParagraph(l, variant, 0)
+		}
+	}
+
+	switch p.kind {
+	case kindAdmonition:
+		label := l.content[:bytes.IndexByte(l.content, ':')]
+		variant, _ := admonitionVariant(string(label))
+		return p.parseAdmonitionParagraph(l, variant, len(label)+2)
+	case kindText, kindDocumentTitle, kindSectionTitleL1, kindSectionTitleL2,
+		kindSectionTitleL3, kindSectionTitleL4, kindSectionTitleL5, lineListContinuation:
+		return p.parseParagraph(l)
+	case kindListItem:
+		return p.parseList(l)
+	case kindDListItem:
+		return p.parseDescriptionList(l)
+	case kindThematicBreak:
+		return p.parseBreak(l, ast.ThematicVariant)
+	case kindPageBreak:
+		return p.parseBreak(l, ast.PageVariant)
+	case kindBlockMacro:
+		return p.parseBlockMacro(l)
+	case blockLiteralParagraph:
+		return p.parseLiteralParagraph(l)
+	case kindListingDelimiter:
+		return p.parseDelimitedLeaf(l, ast.ListingName)
+	case kindLiteralDelimiter:
+		return p.parseDelimitedLeaf(l, ast.LiteralName)
+	case kindPassDelimiter:
+		switch style {
+		case "stem", "latexmath", "asciimath":
+			return p.parseDelimitedLeaf(l, ast.StemName)
+		}
+		return p.parseDelimitedLeaf(l, ast.PassName)
+	case kindQuoteDelimiter:
+		if style == "verse" {
+			return p.parseDelimitedLeaf(l, ast.VerseName)
+		}
+		return p.parseDelimitedParent(l, ast.QuoteName)
+	case kindExampleDelimiter:
+		return p.parseDelimitedParent(l, ast.ExampleName)
+	case kindSidebarDelimiter:
+		return p.parseDelimitedParent(l, ast.SidebarName)
+	case kindOpenDelimiter:
+		return p.parseDelimitedParent(l, ast.OpenName)
+	case kindTableDelimiter:
+		return p.parseTable(l, meta)
+	}
+
+	return nil
+}
+
+// Paragraph is a group of consecutive text lines
+//
+// Paragraph ends on the empty line or at the end of the document
+func (p *parser) parseParagraph(l *line) *ast.LeafBlock {
+	start := p.at(p.lineNum, len(l.spases)+1)
+	end := p.lineEnd(p.lineNum)
+
+	paragraph := &ast.LeafBlock{
+		Name: ast.ParagraphName,
+		Form: ast.ParagraphForm,
+		AbstructBlock: ast.AbstructBlock{
+			Type: ast.BlockType,
+		},
+	}
+
+	if !p.dropsLine(l.content) {
+		paragraph.Inlines = append(paragraph.Inlines, p.parseInlines(l.content, p.lineNum, len(l.spases)+1))
+	}
+
+	for {
+		line := p.nextLine()
+
+		if line == nil {
+			break
+		}
+
+		if p.kind == lineComment {
+			continue
+		}
+
+		if p.interruptsParagraph(p.kind) {
+			p.lineNum--
+			break
+		}
+
+		end = p.lineEnd(p.lineNum)
+
+		if p.dropsLine(line.content) {
+			continue
+		}
+
+		paragraph.Inlines = append(paragraph.Inlines, p.parseInlines(line.content, p.lineNum, len(line.spases)+1))
+	}
+
+	paragraph.Location = []ast.LocationBoundary{start, end}
+
+	return paragraph
+}
+
+// Literal paragraph is a group of consecutive lines where the first one is indented
+//
+// Common indentation is stripped, the rest of the whitespace is kept as is
+func (p *parser) parseLiteralParagraph(l *line) *ast.LeafBlock {
+	var (
+		start = p.lineNum
+		end   = p.lineNum
+
+		indent = len(l.spases)
+	)
+
+	for {
+		line := p.nextLine()
+
+		if line == nil {
+			break
+		}
+
+		if p.interruptsParagraph(p.kind) {
+			p.lineNum--
+			break
+		}
+
+		indent = min(indent, len(line.spases))
+		end = p.lineNum
+	}
+
+	literal := &ast.LeafBlock{
+		Name: ast.LiteralName,
+		Form: ast.IndentedForm,
+		AbstructBlock: ast.AbstructBlock{
+			Type: ast.BlockType,
+			Location: []ast.LocationBoundary{
+				p.at(start, len(l.spases)+1),
+				p.lineEnd(end),
+			},
+		},
+	}
+
+	for x := start; x <= end; x++ {
+		literal.Inlines = append(literal.Inlines, p.parseVerbatim(x, indent))
+	}
+
+	return literal
+}
+
+// Break takes the whole line
+//
+// Pattern: "<<<" for the page break, "---", "***" or three apostrophes for the thematic one
+func (p *parser) parseBreak(l *line, variant ast.Variant) *ast.Break {
+	return &ast.Break{
+		Name:    ast.BreakName,
+		Variant: variant,
+		AbstructBlock: ast.AbstructBlock{
+			Type: ast.BlockType,
+			Location: []ast.LocationBoundary{
+				p.at(p.lineNum, 1),
+				p.lineEnd(p.lineNum),
+			},
+		},
+	}
+}
+
+// Admonition paragraph wraps the paragraph placed after the label
+//
+// Pattern: "NOTE: text" or "[NOTE]" followed by the paragraph
+func (p *parser) parseAdmonitionParagraph(l *line, variant ast.Variant, labelLen int) *ast.ParentBlock {
+	start := p.at(p.lineNum, len(l.spases)+1)
+
+	// Paragraph starts after the label and the following spaces
+	offset := len(l.spases) + labelLen
+	offset += len(p.lines[p.lineNum-1][offset:]) - len(bytes.TrimLeft(p.lines[p.lineNum-1][offset:], " \t"))
+
+	paragraph := p.parseParagraph(&line{
+		spases:  p.lines[p.lineNum-1][:offset],
+		content: p.lines[p.lineNum-1][offset:],
+	})
+
+	return &ast.ParentBlock{
+		Name:    ast.AdmonitionName,
+		Form:    ast.ParagraphForm,
+		Blocks:  []ast.Block{paragraph},
+		Variant: variant,
+		AbstructBlock: ast.AbstructBlock{
+			Type: ast.BlockType,
+			Location: []ast.LocationBoundary{
+				start,
+				paragraph.Location[1],
+			},
+		},
+	}
+}
+
+func admonitionVariant(label string) (ast.Variant, bool) {
+	switch label {
+	case "NOTE":
+		return ast.NoteVariant, true
+	case "TIP":
+		return ast.TipVariant, true
+	case "IMPORTANT":
+		return ast.ImportantVariant, true
+	case "CAUTION":
+		return ast.CautionVariant, true
+	case "WARNING":
+		return ast.WarningVariant, true
+	}
+
+	return "", false
+}
+
+func (p *parser) interruptsParagraph(kind Kind) bool {
+	// Inside the list the next item or attached block ends the paragraph
+	if len(p.lists) > 0 && (kind == kindListItem || kind == kindDListItem || kind == lineListContinuation) {
+		return true
+	}
+
+	switch kind {
+	case lineEmpty, lineBlockAttributes, lineBlockAnchor, lineMultilineComment,
+		kindListingDelimiter, kindLiteralDelimiter, kindPassDelimiter, kindQuoteDelimiter,
+		kindExampleDelimiter, kindSidebarDelimiter, kindOpenDelimiter, kindTableDelimiter:
+		return true
+	}
+
+	return false
+}
+
+// Section
+//
+// Pattern: "== Section title" or "## Section title"
+func (p *parser) parseSection(l *line, level int) *ast.Section {
+	start := p.at(p.lineNum, len(l.spases)+1)
+	end := p.lineEnd(p.lineNum)
+
+	section := &ast.Section{
+		Name: ast.SectionName,
+		AbstractHeading: ast.AbstractHeading{
+			Level: level,
+			AbstructBlock: ast.AbstructBlock{
+				Type: ast.BlockType,
+			},
+		},
+	}
+
+	p.takeMeta().apply(&section.AbstructBlock)
+	section.Title = p.parseHeading(l)
+
+	section.Blocks = p.parseSectionBlocks(level)
+
+	if len(section.Blocks) > 0 {
+		loc := locationOf(section.Blocks[len(section.Blocks)-1])
+		end = loc[len(loc)-1]
+	}
+
+	section.Location = []ast.LocationBoundary{start, end}
+
+	return section
+}
+
+// Discrete heading looks like the section title but has no body
+//
+// Pattern: "[discrete]" or "[float]" followed by "== Heading"
+func (p *parser) parseDiscreteHeading(l *line, level int) *ast.DiscreteHeading {
+	return &ast.DiscreteHeading{
+		Name: ast.DiscreteHeadingName,
+		AbstractHeading: ast.AbstractHeading{
+			Level: level,
+			AbstructBlock: ast.AbstructBlock{
+				Type:  ast.BlockType,
+				Title: p.parseHeading(l),
+				Location: []ast.LocationBoundary{
+					p.at(p.lineNum, len(l.spases)+1),
+					p.lineEnd(p.lineNum),
+				},
+			},
+		},
+	}
+}
+
+func isDiscreteStyle(style string) bool {
+	return style == "discrete" || style == "float"
+}
+
+func sectionLevel(kind Kind) int {
+	switch kind {
+	case kindSectionTitleL1:
+		return 1
+	case kindSectionTitleL2:
+		return 2
+	case kindSectionTitleL3:
+		return 3
+	case kindSectionTitleL4:
+		return 4
+	case kindSectionTitleL5:
+		return 5
+	}
+
+	return 0
+}
+
+// Boundary at the column col of the line lineNum, the line is mapped to its source.
+// File is set for the lines of the included files only.
+func (p *parser) at(lineNum, col int) ast.LocationBoundary {
+	b := ast.LocationBoundary{
+		Line:    p.offset + lineNum,
+		Collumn: p.colBase(lineNum) + col,
+	}
+
+	if b.Line >= 1 && b.Line <= len(p.sources) {
+		s := p.sources[b.Line-1]
+
+		b.Line = s.num
+		if s.file.include != nil {
+			b.File = s.file.name
+		}
+	}
+
+	return b
+}
+
+// Columns before the text of the line lineNum
+func (p *parser) colBase(lineNum int) int {
+	if lineNum < 1 || lineNum > len(p.cols) {
+		return 0
+	}
+
+	return p.cols[lineNum-1]
+}
+
+// End boundary of the line with number lineNum
+func (p *parser) lineEnd(lineNum int) ast.LocationBoundary {
+	return p.at(lineNum, len(p.lines[lineNum-1]))
+}
+
+func locationOf(b ast.Block) ast.Location {
+	return abstractOf(b).Location
+}
+
+func abstractOf(b ast.Block) *ast.AbstructBlock {
+	switch b := b.(type) {
+	case *ast.Section:
+		return &b.AbstructBlock
+	case *ast.List:
+		return &b.AbstructBlock
+	case *ast.DescriptionList:
+		return &b.AbstructBlock
+	case *ast.DiscreteHeading:
+		return &b.AbstructBlock
+	case *ast.Break:
+		return &b.AbstructBlock
+	case *ast.BlockMacro:
+		return &b.AbstructBlock
+	case *ast.LeafBlock:
+		return &b.AbstructBlock
+	case *ast.ParentBlock:
+		return &b.AbstructBlock
+	case *ast.Table:
+		return &b.AbstructBlock
+	}
+
+	return nil
+}
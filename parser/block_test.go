@@ -0,0 +1,844 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func text(value string, line, col int) *ast.InlineLiteral {
+	return &ast.InlineLiteral{
+		Name:  ast.TextName,
+		Type:  ast.StringType,
+		Value: value,
+		Location: []ast.LocationBoundary{
+			{
+				Line:    line,
+				Collumn: col,
+			},
+			{
+				Line:    line,
+				Collumn: col + len(value) - 1,
+			},
+		},
+	}
+}
+
+func location(startLine, startCol, endLine, endCol int) ast.Location {
+	return []ast.LocationBoundary{
+		{
+			Line:    startLine,
+			Collumn: startCol,
+		},
+		{
+			Line:    endLine,
+			Collumn: endCol,
+		},
+	}
+}
+
+// Metadata of the block attribute line "[style]"
+func styleMeta(style string, line int) ast.BlockMetaData {
+	return ast.BlockMetaData{
+		Attributes: map[string]string{
+			"$1": style,
+		},
+		Location: location(line, 1, line, len(style)+2),
+	}
+}
+
+func TestParseSections(t *testing.T) {
+
+	const (
+		SingleSection   = "Single section"
+		NestedSections  = "Nested sections"
+		SiblingSections = "Sibling sections"
+		MarkdownTitles  = "Markdown titles"
+		NoSpace         = "Marker without space"
+	)
+
+	cases := map[string][]string{
+		SingleSection: {
+			"= Document Title",
+			"",
+			"== Section",
+		},
+		NestedSections: {
+			"== First",
+			"",
+			"=== Second",
+			"",
+			"==== Third",
+		},
+		SiblingSections: {
+			"== First",
+			"=== Nested",
+			"== Second",
+		},
+		MarkdownTitles: {
+			"## First",
+			"",
+			"### Second",
+		},
+		NoSpace: {
+			"==\u00a0Section",
+			"",
+			"#\r0",
+		},
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []ast.Block
+	}{
+		{
+			name:  SingleSection,
+			input: []byte(strings.Join(cases[SingleSection], "\n")),
+			want: []ast.Block{
+				&ast.Section{
+					Name: ast.SectionName,
+					AbstractHeading: ast.AbstractHeading{
+						Level: 1,
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Title:    ast.Inlines{text("Section", 3, 4)},
+							Location: location(3, 1, 3, 10),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  NestedSections,
+			input: []byte(strings.Join(cases[NestedSections], "\n")),
+			want: []ast.Block{
+				&ast.Section{
+					Name: ast.SectionName,
+					Blocks: []ast.Block{
+						&ast.Section{
+							Name: ast.SectionName,
+							Blocks: []ast.Block{
+								&ast.Section{
+									Name: ast.SectionName,
+									AbstractHeading: ast.AbstractHeading{
+										Level: 3,
+										AbstructBlock: ast.AbstructBlock{
+											Type:     ast.BlockType,
+											Title:    ast.Inlines{text("Third", 5, 6)},
+											Location: location(5, 1, 5, 10),
+										},
+									},
+								},
+							},
+							AbstractHeading: ast.AbstractHeading{
+								Level: 2,
+								AbstructBlock: ast.AbstructBlock{
+									Type:     ast.BlockType,
+									Title:    ast.Inlines{text("Second", 3, 5)},
+									Location: location(3, 1, 5, 10),
+								},
+							},
+						},
+					},
+					AbstractHeading: ast.AbstractHeading{
+						Level: 1,
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Title:    ast.Inlines{text("First", 1, 4)},
+							Location: location(1, 1, 5, 10),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  SiblingSections,
+			input: []byte(strings.Join(cases[SiblingSections], "\n")),
+			want: []ast.Block{
+				&ast.Section{
+					Name: ast.SectionName,
+					Blocks: []ast.Block{
+						&ast.Section{
+							Name: ast.SectionName,
+							AbstractHeading: ast.AbstractHeading{
+								Level: 2,
+								AbstructBlock: ast.AbstructBlock{
+									Type:     ast.BlockType,
+									Title:    ast.Inlines{text("Nested", 2, 5)},
+									Location: location(2, 1, 2, 10),
+								},
+							},
+						},
+					},
+					AbstractHeading: ast.AbstractHeading{
+						Level: 1,
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Title:    ast.Inlines{text("First", 1, 4)},
+							Location: location(1, 1, 2, 10),
+						},
+					},
+				},
+				&ast.Section{
+					Name: ast.SectionName,
+					AbstractHeading: ast.AbstractHeading{
+						Level: 1,
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Title:    ast.Inlines{text("Second", 3, 4)},
+							Location: location(3, 1, 3, 9),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  MarkdownTitles,
+			input: []byte(strings.Join(cases[MarkdownTitles], "\n")),
+			want: []ast.Block{
+				&ast.Section{
+					Name: ast.SectionName,
+					Blocks: []ast.Block{
+						&ast.Section{
+							Name: ast.SectionName,
+							AbstractHeading: ast.AbstractHeading{
+								Level: 2,
+								AbstructBlock: ast.AbstructBlock{
+									Type:     ast.BlockType,
+									Title:    ast.Inlines{text("Second", 3, 5)},
+									Location: location(3, 1, 3, 10),
+								},
+							},
+						},
+					},
+					AbstractHeading: ast.AbstractHeading{
+						Level: 1,
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Title:    ast.Inlines{text("First", 1, 4)},
+							Location: location(1, 1, 3, 10),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  NoSpace,
+			input: []byte(strings.Join(cases[NoSpace], "\n")),
+			want: []ast.Block{
+				&ast.LeafBlock{
+					Name:    ast.ParagraphName,
+					Form:    ast.ParagraphForm,
+					Inlines: []ast.Inlines{{text("==\u00a0Section", 1, 1)}},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(1, 1, 1, 11),
+					},
+				},
+				&ast.LeafBlock{
+					Name:    ast.ParagraphName,
+					Form:    ast.ParagraphForm,
+					Inlines: []ast.Inlines{{text("#\r0", 3, 1)}},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(3, 1, 3, 3),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseParagraphs(t *testing.T) {
+
+	const (
+		SingleLine      = "Single line"
+		MultiLine       = "Multi line"
+		TwoParagraphs   = "Two paragraphs"
+		AfterHeader     = "After header"
+		InSection       = "In section"
+		WithLineComment = "With line comment"
+	)
+
+	cases := map[string][]string{
+		SingleLine: {
+			"Some text",
+		},
+		MultiLine: {
+			"First line",
+			"  second line",
+		},
+		TwoParagraphs: {
+			"First",
+			"",
+			"",
+			"Second",
+		},
+		AfterHeader: {
+			"= Document Title",
+			"Author Name",
+			"",
+			"Body text",
+		},
+		InSection: {
+			"== Section",
+			"Body text",
+		},
+		WithLineComment: {
+			"First line",
+			"// comment",
+			"second line",
+		},
+	}
+
+	paragraph := func(loc ast.Location, lines ...ast.Inlines) *ast.LeafBlock {
+		return &ast.LeafBlock{
+			Name:    ast.ParagraphName,
+			Form:    ast.ParagraphForm,
+			Inlines: lines,
+			AbstructBlock: ast.AbstructBlock{
+				Type:     ast.BlockType,
+				Location: loc,
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []ast.Block
+	}{
+		{
+			name:  SingleLine,
+			input: []byte(strings.Join(cases[SingleLine], "\n")),
+			want: []ast.Block{
+				paragraph(location(1, 1, 1, 9),
+					ast.Inlines{text("Some text", 1, 1)},
+				),
+			},
+		},
+		{
+			name:  MultiLine,
+			input: []byte(strings.Join(cases[MultiLine], "\n")),
+			want: []ast.Block{
+				paragraph(location(1, 1, 2, 13),
+					ast.Inlines{text("First line", 1, 1)},
+					ast.Inlines{text("second line", 2, 3)},
+				),
+			},
+		},
+		{
+			name:  TwoParagraphs,
+			input: []byte(strings.Join(cases[TwoParagraphs], "\n")),
+			want: []ast.Block{
+				paragraph(location(1, 1, 1, 5),
+					ast.Inlines{text("First", 1, 1)},
+				),
+				paragraph(location(4, 1, 4, 6),
+					ast.Inlines{text("Second", 4, 1)},
+				),
+			},
+		},
+		{
+			name:  AfterHeader,
+			input: []byte(strings.Join(cases[AfterHeader], "\n")),
+			want: []ast.Block{
+				paragraph(location(4, 1, 4, 9),
+					ast.Inlines{text("Body text", 4, 1)},
+				),
+			},
+		},
+		{
+			name:  InSection,
+			input: []byte(strings.Join(cases[InSection], "\n")),
+			want: []ast.Block{
+				&ast.Section{
+					Name: ast.SectionName,
+					Blocks: []ast.Block{
+						paragraph(location(2, 1, 2, 9),
+							ast.Inlines{text("Body text", 2, 1)},
+						),
+					},
+					AbstractHeading: ast.AbstractHeading{
+						Level: 1,
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Title:    ast.Inlines{text("Section", 1, 4)},
+							Location: location(1, 1, 2, 9),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  WithLineComment,
+			input: []byte(strings.Join(cases[WithLineComment], "\n")),
+			want: []ast.Block{
+				paragraph(location(1, 1, 3, 11),
+					ast.Inlines{text("First line", 1, 1)},
+					ast.Inlines{text("second line", 3, 1)},
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLiteralParagraphs(t *testing.T) {
+
+	const (
+		SingleLine   = "Single line"
+		CommonIndent = "Common indent"
+		Unindented   = "Unindented continuation"
+	)
+
+	cases := map[string][]string{
+		SingleLine: {
+			" literal text",
+		},
+		CommonIndent: {
+			"  first",
+			"    second",
+			"  third",
+		},
+		Unindented: {
+			"  first",
+			"second",
+			"",
+			"Paragraph",
+		},
+	}
+
+	literal := func(loc ast.Location, lines ...ast.Inlines) *ast.LeafBlock {
+		return &ast.LeafBlock{
+			Name:    ast.LiteralName,
+			Form:    ast.IndentedForm,
+			Inlines: lines,
+			AbstructBlock: ast.AbstructBlock{
+				Type:     ast.BlockType,
+				Location: loc,
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []ast.Block
+	}{
+		{
+			name:  SingleLine,
+			input: []byte(strings.Join(cases[SingleLine], "\n")),
+			want: []ast.Block{
+				literal(location(1, 2, 1, 13),
+					ast.Inlines{text("literal text", 1, 2)},
+				),
+			},
+		},
+		{
+			name:  CommonIndent,
+			input: []byte(strings.Join(cases[CommonIndent], "\n")),
+			want: []ast.Block{
+				literal(location(1, 3, 3, 7),
+					ast.Inlines{text("first", 1, 3)},
+					ast.Inlines{text("  second", 2, 3)},
+					ast.Inlines{text("third", 3, 3)},
+				),
+			},
+		},
+		{
+			name:  Unindented,
+			input: []byte(strings.Join(cases[Unindented], "\n")),
+			want: []ast.Block{
+				literal(location(1, 3, 2, 6),
+					ast.Inlines{text("  first", 1, 1)},
+					ast.Inlines{text("second", 2, 1)},
+				),
+				&ast.LeafBlock{
+					Name:    ast.ParagraphName,
+					Form:    ast.ParagraphForm,
+					Inlines: []ast.Inlines{{text("Paragraph", 4, 1)}},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(4, 1, 4, 9),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAdmonitions(t *testing.T) {
+
+	const (
+		Paragraph      = "Paragraph"
+		StyledBlock    = "Styled block"
+		StyledParagrah = "Styled paragraph"
+		NotLabel       = "Not a label"
+	)
+
+	cases := map[string][]string{
+		Paragraph: {
+			"NOTE: Some note",
+			"continues here",
+		},
+		StyledBlock: {
+			"[WARNING]",
+			"====",
+			"Be careful",
+			"====",
+		},
+		StyledParagrah: {
+			"[TIP]",
+			"Useful tip",
+		},
+		NotLabel: {
+			"NOTE:no space",
+		},
+	}
+
+	paragraph := func(loc ast.Location, lines ...ast.Inlines) *ast.LeafBlock {
+		return &ast.LeafBlock{
+			Name:    ast.ParagraphName,
+			Form:    ast.ParagraphForm,
+			Inlines: lines,
+			AbstructBlock: ast.AbstructBlock{
+				Type:     ast.BlockType,
+				Location: loc,
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []ast.Block
+	}{
+		{
+			name:  Paragraph,
+			input: []byte(strings.Join(cases[Paragraph], "\n")),
+			want: []ast.Block{
+				&ast.ParentBlock{
+					Name: ast.AdmonitionName,
+					Form: ast.ParagraphForm,
+					Blocks: []ast.Block{
+						paragraph(location(1, 7, 2, 14),
+							ast.Inlines{text("Some note", 1, 7)},
+							ast.Inlines{text("continues here", 2, 1)},
+						),
+					},
+					Variant: ast.NoteVariant,
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(1, 1, 2, 14),
+					},
+				},
+			},
+		},
+		{
+			name:  StyledBlock,
+			input: []byte(strings.Join(cases[StyledBlock], "\n")),
+			want: []ast.Block{
+				&ast.ParentBlock{
+					Name:      ast.AdmonitionName,
+					Form:      ast.DelimitedForm,
+					Delimiter: "====",
+					Blocks: []ast.Block{
+						paragraph(location(3, 1, 3, 10),
+							ast.Inlines{text("Be careful", 3, 1)},
+						),
+					},
+					Variant: ast.WarningVariant,
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						MetaData: styleMeta("WARNING", 1),
+						Location: location(2, 1, 4, 4),
+					},
+				},
+			},
+		},
+		{
+			name:  StyledParagrah,
+			input: []byte(strings.Join(cases[StyledParagrah], "\n")),
+			want: []ast.Block{
+				&ast.ParentBlock{
+					Name: ast.AdmonitionName,
+					Form: ast.ParagraphForm,
+					Blocks: []ast.Block{
+						paragraph(location(2, 1, 2, 10),
+							ast.Inlines{text("Useful tip", 2, 1)},
+						),
+					},
+					Variant: ast.TipVariant,
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						MetaData: styleMeta("TIP", 1),
+						Location: location(2, 1, 2, 10),
+					},
+				},
+			},
+		},
+		{
+			name:  NotLabel,
+			input: []byte(strings.Join(cases[NotLabel], "\n")),
+			want: []ast.Block{
+				paragraph(location(1, 1, 1, 13),
+					ast.Inlines{text("NOTE:no space", 1, 1)},
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBreaks(t *testing.T) {
+
+	const (
+		Thematic = "Thematic"
+		Markdown = "Markdown"
+		Page     = "Page"
+	)
+
+	cases := map[string][]string{
+		Thematic: {
+			"'''",
+		},
+		Markdown: {
+			"---",
+			"",
+			"* * *",
+		},
+		Page: {
+			"Text",
+			"",
+			"<<<",
+		},
+	}
+
+	breakBlock := func(variant ast.Variant, loc ast.Location) *ast.Break {
+		return &ast.Break{
+			Name:    ast.BreakName,
+			Variant: variant,
+			AbstructBlock: ast.AbstructBlock{
+				Type:     ast.BlockType,
+				Location: loc,
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []ast.Block
+	}{
+		{
+			name:  Thematic,
+			input: []byte(strings.Join(cases[Thematic], "\n")),
+			want: []ast.Block{
+				breakBlock(ast.ThematicVariant, location(1, 1, 1, 3)),
+			},
+		},
+		{
+			name:  Markdown,
+			input: []byte(strings.Join(cases[Markdown], "\n")),
+			want: []ast.Block{
+				breakBlock(ast.ThematicVariant, location(1, 1, 1, 3)),
+				breakBlock(ast.ThematicVariant, location(3, 1, 3, 5)),
+			},
+		},
+		{
+			name:  Page,
+			input: []byte(strings.Join(cases[Page], "\n")),
+			want: []ast.Block{
+				&ast.LeafBlock{
+					Name:    ast.ParagraphName,
+					Form:    ast.ParagraphForm,
+					Inlines: []ast.Inlines{{text("Text", 1, 1)}},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(1, 1, 1, 4),
+					},
+				},
+				breakBlock(ast.PageVariant, location(3, 1, 3, 3)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDiscreteHeadings(t *testing.T) {
+
+	const (
+		InSection = "In section"
+		Float     = "Float in block"
+	)
+
+	cases := map[string][]string{
+		InSection: {
+			"== First",
+			"[discrete]",
+			"== Heading",
+			"Text",
+			"",
+			"== Second",
+		},
+		Float: {
+			"====",
+			"[float]",
+			"=== Heading",
+			"====",
+		},
+	}
+
+	heading := func(value string, level, line int, meta ast.BlockMetaData) *ast.DiscreteHeading {
+		return &ast.DiscreteHeading{
+			Name: ast.DiscreteHeadingName,
+			AbstractHeading: ast.AbstractHeading{
+				Level: level,
+				AbstructBlock: ast.AbstructBlock{
+					Type:     ast.BlockType,
+					Title:    ast.Inlines{text(value, line, level+3)},
+					MetaData: meta,
+					Location: location(line, 1, line, len(value)+level+2),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []ast.Block
+	}{
+		{
+			name:  InSection,
+			input: []byte(strings.Join(cases[InSection], "\n")),
+			want: []ast.Block{
+				&ast.Section{
+					Name: ast.SectionName,
+					Blocks: []ast.Block{
+						heading("Heading", 1, 3, styleMeta("discrete", 2)),
+						&ast.LeafBlock{
+							Name:    ast.ParagraphName,
+							Form:    ast.ParagraphForm,
+							Inlines: []ast.Inlines{{text("Text", 4, 1)}},
+							AbstructBlock: ast.AbstructBlock{
+								Type:     ast.BlockType,
+								Location: location(4, 1, 4, 4),
+							},
+						},
+					},
+					AbstractHeading: ast.AbstractHeading{
+						Level: 1,
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Title:    ast.Inlines{text("First", 1, 4)},
+							Location: location(1, 1, 4, 4),
+						},
+					},
+				},
+				&ast.Section{
+					Name: ast.SectionName,
+					AbstractHeading: ast.AbstractHeading{
+						Level: 1,
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Title:    ast.Inlines{text("Second", 6, 4)},
+							Location: location(6, 1, 6, 9),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  Float,
+			input: []byte(strings.Join(cases[Float], "\n")),
+			want: []ast.Block{
+				&ast.ParentBlock{
+					Name:      ast.ExampleName,
+					Form:      ast.DelimitedForm,
+					Delimiter: "====",
+					Blocks: []ast.Block{
+						heading("Heading", 2, 3, styleMeta("float", 2)),
+					},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(1, 1, 4, 4),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+		})
+	}
+}
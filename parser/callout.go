@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+// Callout marker at the end of the line, may be hidden behind the line comment
+//
+// Pattern: "<1>", "<.>", "// <1>", "# <1>"
+var calloutRx = regexp.MustCompile(`[ \t]*(?:(?://|#|--|;;) ?)?<(\d+|\.)>$`)
+
+// Callout markers at the end of the verbatim line
+//
+// Returns the line without markers. Auto numbered markers "<.>"
+// continue the numbering after prev markers of the block.
+func (p *parser) parseCallouts(content []byte, lineNum, prev int) ([]byte, []ast.Callout) {
+	var callouts []ast.Callout
+
+	for {
+		m := calloutRx.FindSubmatchIndex(content)
+
+		if m == nil {
+			break
+		}
+
+		marker := content[m[2]-1 : m[3]+1]
+
+		callouts = append(callouts, ast.Callout{
+			Location: []ast.LocationBoundary{
+				p.at(lineNum, m[2]),
+				p.at(lineNum, m[3]+1),
+			},
+		})
+
+		number, err := strconv.Atoi(string(marker[1 : len(marker)-1]))
+		if err == nil {
+			callouts[len(callouts)-1].Number = number
+		}
+
+		content = content[:m[0]]
+	}
+
+	slices.Reverse(callouts)
+
+	for x := range callouts {
+		if callouts[x].Number == 0 {
+			callouts[x].Number = prev + x + 1
+		}
+	}
+
+	return content, callouts
+}
+
+// Each item of the callout list must refer to the marker of the verbatim block right before it
+func (p *parser) checkCalloutList(list *ast.List) {
+	for x, item := range list.Items {
+		number := x + 1
+
+		if item.Marker != "<.>" {
+			number, _ = strconv.Atoi(item.Marker[1 : len(item.Marker)-1])
+		}
+
+		if !slices.Contains(p.callouts, number) {
+			start := item.Location[0]
+			p.reportIn(cmp.Or(start.File, p.name), start.Line, "no callout found for <%d>", number)
+		}
+	}
+
+	p.callouts = nil
+}
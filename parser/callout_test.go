@@ -0,0 +1,220 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func TestParseCallouts(t *testing.T) {
+
+	const (
+		Numbered = "Numbered"
+		Auto     = "Auto numbered"
+		Missing  = "Missing callout"
+		Earlier  = "Callout of the earlier block"
+	)
+
+	cases := map[string][]string{
+		Numbered: {
+			"----",
+			"x := 1 // <1>",
+			"y := 2 <2> <3>",
+			"----",
+			"<1> First",
+			"<2> Second",
+			"<3> Third",
+		},
+		Auto: {
+			"....",
+			"one <.>",
+			"....",
+			"<.> First",
+		},
+		Missing: {
+			"----",
+			"code <1>",
+			"----",
+			"<2> Unknown",
+		},
+		Earlier: {
+			"----",
+			"a <1>",
+			"----",
+			"",
+			"Text",
+			"",
+			"----",
+			"b",
+			"----",
+			"<1> First",
+		},
+	}
+
+	callout := func(number, line, col int) ast.Callout {
+		return ast.Callout{
+			Number:   number,
+			Location: location(line, col, line, col+2),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		input       []byte
+		want        []ast.Block
+		diagnostics []Diagnostic
+	}{
+		{
+			name:  Numbered,
+			input: []byte(strings.Join(cases[Numbered], "\n")),
+			want: []ast.Block{
+				&ast.LeafBlock{
+					Name: ast.ListingName,
+					Form: ast.DelimitedForm,
+					Inlines: []ast.Inlines{
+						{text("x := 1", 2, 1)},
+						{text("y := 2", 3, 1)},
+					},
+					Delimiter: "----",
+					Callouts: []ast.Callout{
+						callout(1, 2, 11),
+						callout(2, 3, 8),
+						callout(3, 3, 12),
+					},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(1, 1, 4, 4),
+					},
+				},
+				list("<1>", ast.CalloutVariant, location(5, 1, 7, 9),
+					listItem("<1>", location(5, 1, 5, 9), ast.Inlines{text("First", 5, 5)}),
+					listItem("<2>", location(6, 1, 6, 10), ast.Inlines{text("Second", 6, 5)}),
+					listItem("<3>", location(7, 1, 7, 9), ast.Inlines{text("Third", 7, 5)}),
+				),
+			},
+		},
+		{
+			name:  Auto,
+			input: []byte(strings.Join(cases[Auto], "\n")),
+			want: []ast.Block{
+				&ast.LeafBlock{
+					Name: ast.LiteralName,
+					Form: ast.DelimitedForm,
+					Inlines: []ast.Inlines{
+						{text("one", 2, 1)},
+					},
+					Delimiter: "....",
+					Callouts: []ast.Callout{
+						callout(1, 2, 5),
+					},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(1, 1, 3, 4),
+					},
+				},
+				list("<.>", ast.CalloutVariant, location(4, 1, 4, 9),
+					listItem("<.>", location(4, 1, 4, 9), ast.Inlines{text("First", 4, 5)}),
+				),
+			},
+		},
+		{
+			name:  Missing,
+			input: []byte(strings.Join(cases[Missing], "\n")),
+			want: []ast.Block{
+				&ast.LeafBlock{
+					Name: ast.ListingName,
+					Form: ast.DelimitedForm,
+					Inlines: []ast.Inlines{
+						{text("code", 2, 1)},
+					},
+					Delimiter: "----",
+					Callouts: []ast.Callout{
+						callout(1, 2, 6),
+					},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(1, 1, 3, 4),
+					},
+				},
+				list("<2>", ast.CalloutVariant, location(4, 1, 4, 11),
+					listItem("<2>", location(4, 1, 4, 11), ast.Inlines{text("Unknown", 4, 5)}),
+				),
+			},
+			diagnostics: []Diagnostic{
+				{
+					Line:    4,
+					Message: "no callout found for <2>",
+				},
+			},
+		},
+		{
+			name:  Earlier,
+			input: []byte(strings.Join(cases[Earlier], "\n")),
+			want: []ast.Block{
+				&ast.LeafBlock{
+					Name: ast.ListingName,
+					Form: ast.DelimitedForm,
+					Inlines: []ast.Inlines{
+						{text("a", 2, 1)},
+					},
+					Delimiter: "----",
+					Callouts: []ast.Callout{
+						callout(1, 2, 3),
+					},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(1, 1, 3, 4),
+					},
+				},
+				&ast.LeafBlock{
+					Name:    ast.ParagraphName,
+					Form:    ast.ParagraphForm,
+					Inlines: []ast.Inlines{{text("Text", 5, 1)}},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(5, 1, 5, 4),
+					},
+				},
+				&ast.LeafBlock{
+					Name: ast.ListingName,
+					Form: ast.DelimitedForm,
+					Inlines: []ast.Inlines{
+						{text("b", 8, 1)},
+					},
+					Delimiter: "----",
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(7, 1, 9, 4),
+					},
+				},
+				list("<1>", ast.CalloutVariant, location(10, 1, 10, 9),
+					listItem("<1>", location(10, 1, 10, 9), ast.Inlines{text("First", 10, 5)}),
+				),
+			},
+			diagnostics: []Diagnostic{
+				{
+					Line:    10,
+					Message: "no callout found for <1>",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+
+			if !reflect.DeepEqual(p.diagnostics, tt.diagnostics) {
+				t.Errorf("parseDocument() diagnostics = %v, want %v", p.diagnostics, tt.diagnostics)
+			}
+		})
+	}
+}
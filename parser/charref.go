@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+var charRefRx = regexp.MustCompile(`^&(?:[a-zA-Z][a-zA-Z0-9]+|#[0-9]{2,6}|#x[0-9a-fA-F]{2,5});`)
+
+// Typographic replacements and their character references
+var replacements = []struct {
+	text string
+	ref  string
+}{
+	{"(C)", "&#169;"},
+	{"(R)", "&#174;"},
+	{"(TM)", "&#8482;"},
+	{"...", "&#8230;"},
+	{"->", "&#8594;"},
+	{"=>", "&#8658;"},
+	{"<-", "&#8592;"},
+	{"<=", "&#8656;"},
+}
+
+// Character reference or typographic replacement which starts at text[x]
+//
+// Pattern: "&amp;", "&#8212;", "&#x2014;" are kept as is,
+// "(C)", "(R)", "(TM)", "--", "...", "->", "=>", "<-", "<=" and the apostrophe
+// between letters like "it's" are replaced with the character references
+func (p *parser) parseCharRef(text []byte, x, lineNum, col int) (ast.Inline, int) {
+	rest := text[x:]
+
+	if m := charRefRx.Find(rest); m != nil {
+		return p.newCharRef(string(m), x, len(m), lineNum, col), len(m)
+	}
+
+	for _, r := range replacements {
+		if bytes.HasPrefix(rest, []byte(r.text)) {
+			return p.newCharRef(r.ref, x, len(r.text), lineNum, col), len(r.text)
+		}
+	}
+
+	// Em dash is the double hyphen between words or surrounded by spaces
+	if bytes.HasPrefix(rest, []byte("--")) {
+		before := x == 0 || isWordByte(text[x-1]) || isSpace(text[x-1])
+		after := x+2 == len(text) || isWordByte(text[x+2]) || isSpace(text[x+2])
+
+		if before && after {
+			return p.newCharRef("&#8212;", x, 2, lineNum, col), 2
+		}
+	}
+
+	// Curly apostrophe between letters
+	if rest[0] == '\'' && x > 0 && x+1 < len(text) && isWordByte(text[x-1]) && isWordByte(text[x+1]) {
+		return p.newCharRef("&#8217;", x, 1, lineNum, col), 1
+	}
+
+	return nil, 0
+}
+
+// Character reference which takes n bytes of the text starting at x
+func (p *parser) newCharRef(value string, x, n, lineNum, col int) *ast.InlineLiteral {
+	return &ast.InlineLiteral{
+		Name:  ast.CharRefName,
+		Type:  ast.StringType,
+		Value: value,
+		Location: []ast.LocationBoundary{
+			p.at(lineNum, col+x),
+			p.at(lineNum, col+x+n-1),
+		},
+	}
+}
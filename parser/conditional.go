@@ -0,0 +1,234 @@
+package parser
+
+import (
+	"bytes"
+	"cmp"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var conditionalRx = regexp.MustCompile(`^(ifdef|ifndef|ifeval|endif)::(\S*?(?:([,+])\S*?)?)\[(.*)\]$`)
+
+// Conditional directive waiting for endif
+type conditional struct {
+	directive string // directive line, like "ifdef::name[]"
+	target    string
+
+	// Source of the directive line for the diagnostics
+	file string
+	num  int
+
+	// Lines up to endif are dropped
+	skip bool
+}
+
+// Lines of the false conditional are dropped
+func (p *parser) skipping() bool {
+	return len(p.conditionals) > 0 && p.conditionals[len(p.conditionals)-1].skip
+}
+
+// Conditional directive is evaluated against the attributes at the line x, the directive line is dropped
+//
+// Pattern: "ifdef::a[]", "ifdef::a,b[]" any is defined, "ifdef::a+b[]" all are defined,
+// "ifndef::a[]" is the opposite, "ifeval::[{x} > 2]" compares the values, "endif::[]" or "endif::a[]".
+//
+// Single line form "ifdef::a[text]" is replaced with the text if true.
+// "ifndef::a,b[]" is true if none is defined, "ifndef::a+b[]" if not all are defined.
+func (p *parser) conditional(x int) {
+	lineNum := x + 1
+
+	m := conditionalRx.FindSubmatch(p.lines[x])
+
+	var (
+		name      = string(m[1])
+		target    = string(m[2])
+		delimiter = string(m[3])
+		text      = m[4]
+
+		directive = string(p.lines[x])
+	)
+
+	file, num := p.sourceOf(lineNum)
+
+	drop := func() {
+		p.replaceLine(x, nil, nil)
+	}
+
+	if name == "endif" {
+		defer drop()
+
+		if len(text) > 0 {
+			p.reportIn(file, num, "malformed conditional directive: %s", directive)
+			return
+		}
+
+		if len(p.conditionals) == 0 {
+			p.reportIn(file, num, "%s without the opening conditional", directive)
+			return
+		}
+
+		open := p.conditionals[len(p.conditionals)-1]
+
+		if target != "" && target != open.target {
+			p.reportIn(open.file, open.num, "%s conditional closed by mismatched %s", open.directive, directive)
+			return
+		}
+
+		p.conditionals = p.conditionals[:len(p.conditionals)-1]
+		return
+	}
+
+	malformed := target == ""
+	if name == "ifeval" {
+		malformed = target != "" || len(text) == 0
+	}
+
+	if malformed {
+		p.reportIn(file, num, "malformed conditional directive: %s", directive)
+		drop()
+		return
+	}
+
+	// Single line form of the skipped region is dropped too
+	if p.skipping() {
+		if name == "ifeval" || len(text) == 0 {
+			p.conditionals = append(p.conditionals, conditional{
+				directive: directive,
+				target:    target,
+				file:      file,
+				num:       num,
+				skip:      true,
+			})
+		}
+		drop()
+		return
+	}
+
+	var ok bool
+
+	switch name {
+	case "ifdef":
+		ok = p.definedAttributes(target, delimiter)
+	case "ifndef":
+		ok = !p.definedAttributes(target, delimiter)
+	case "ifeval":
+		var valid bool
+		ok, valid = p.evaluate(text, lineNum)
+		if !valid {
+			p.reportIn(file, num, "malformed conditional directive: %s", directive)
+			drop()
+			return
+		}
+	}
+
+	if name != "ifeval" && len(text) > 0 {
+		if !ok {
+			drop()
+			return
+		}
+
+		// Text keeps the source of the directive line
+		p.lines[x] = text
+		return
+	}
+
+	p.conditionals = append(p.conditionals, conditional{
+		directive: directive,
+		target:    target,
+		file:      file,
+		num:       num,
+		skip:      !ok,
+	})
+
+	drop()
+}
+
+// Any of "a,b" or all of "a+b" attributes are defined
+func (p *parser) definedAttributes(target, delimiter string) bool {
+	names := []string{target}
+	if delimiter != "" {
+		names = strings.Split(target, delimiter)
+	}
+
+	for _, name := range names {
+		_, defined := p.attributes[name]
+
+		if delimiter == "," && defined {
+			return true
+		}
+
+		if delimiter != "," && !defined {
+			return false
+		}
+	}
+
+	return delimiter != ","
+}
+
+var ifevalRx = regexp.MustCompile(`^(.+?)\s*(==|!=|<=|>=|<|>)\s*(.+)$`)
+
+// Comparison of the ifeval expression, the values are compared as numbers if both are numbers
+//
+// Pattern: "{x} > 2", "\"{backend}\" == \"html5\""
+//
+// valid is false if the expression is not the comparison.
+func (p *parser) evaluate(expr []byte, lineNum int) (result, valid bool) {
+	m := ifevalRx.FindSubmatch(bytes.TrimSpace(expr))
+	if m == nil {
+		return false, false
+	}
+
+	lhs, lnum, lok := p.evalValue(m[1], lineNum)
+	rhs, rnum, rok := p.evalValue(m[3], lineNum)
+
+	c := strings.Compare(lhs, rhs)
+	if lok && rok {
+		c = cmp.Compare(lnum, rnum)
+	}
+
+	switch string(m[2]) {
+	case "==":
+		return c == 0, true
+	case "!=":
+		return c != 0, true
+	case "<":
+		return c < 0, true
+	case "<=":
+		return c <= 0, true
+	case ">":
+		return c > 0, true
+	default:
+		return c >= 0, true
+	}
+}
+
+// Value of the ifeval operand, quoted one is always the string, the missing attribute is empty
+func (p *parser) evalValue(operand []byte, lineNum int) (text string, num float64, isNum bool) {
+	operand = bytes.TrimSpace(operand)
+
+	quoted := len(operand) >= 2 && (operand[0] == '"' || operand[0] == '\'') && operand[len(operand)-1] == operand[0]
+	if quoted {
+		operand = operand[1 : len(operand)-1]
+	}
+
+	// References to the missing attributes are dropped from the operand whatever "attribute-missing" is
+	missing, set := p.attributes["attribute-missing"]
+	p.attributes["attribute-missing"] = "drop"
+
+	text = strings.TrimSpace(string(p.substituteAttributes(operand, lineNum)))
+
+	if set {
+		p.attributes["attribute-missing"] = missing
+	} else {
+		delete(p.attributes, "attribute-missing")
+	}
+
+	if quoted {
+		return text, 0, false
+	}
+
+	num, err := strconv.ParseFloat(text, 64)
+
+	return text, num, err == nil
+}
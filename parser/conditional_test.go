@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func TestConditionalDirectives(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []string
+		want        []string
+		locations   []ast.Location
+		diagnostics []Diagnostic
+	}{
+		{
+			name:  "Ifdef",
+			input: []string{":a:", "ifdef::a[]", "shown", "endif::a[]", "ifdef::b[]", "hidden", "endif::[]"},
+			want:  []string{":a:", "shown"},
+			locations: []ast.Location{
+				location(3, 1, 3, 5),
+			},
+		},
+		{
+			name:  "Any and all",
+			input: []string{":a:", "ifdef::a,b[]", "any", "endif::[]", "ifdef::a+b[]", "all", "endif::[]"},
+			want:  []string{":a:", "any"},
+		},
+		{
+			name:  "Ifndef",
+			input: []string{":a:", "ifndef::b[]", "shown", "endif::[]", "ifndef::a,b[]", "hidden", "endif::[]", "ifndef::a+b[]", "not all", "endif::[]"},
+			want:  []string{":a:", "shown", "not all"},
+		},
+		{
+			name:  "Single line",
+			input: []string{":a:", "ifdef::a[shown *text*]", "ifdef::b[hidden]"},
+			want:  []string{":a:", "shown *text*"},
+		},
+		{
+			name:  "Ifeval",
+			input: []string{":x: 3", ":backend: html5", `ifeval::[{x} > 2]`, "number", "endif::[]", `ifeval::["{backend}" != "html5"]`, "string", "endif::[]"},
+			want:  []string{":x: 3", ":backend: html5", "number"},
+		},
+		{
+			name:  "Ifeval with missing attribute",
+			input: []string{`ifeval::[{undefined-x} > 2]`, "hidden", "endif::[]", `ifeval::["{undefined-x}" == ""]`, "empty", "endif::[]"},
+			want:  []string{"empty"},
+		},
+		{
+			name:  "Nested in the skipped region",
+			input: []string{"ifdef::b[]", ":a:", "ifdef::a[]", "hidden", "endif::a[]", "endif::b[]", "ifdef::a[]", "hidden", "endif::[]"},
+			want:  []string{""},
+		},
+		{
+			name:  "Attribute entry of the body",
+			input: []string{"first", "", ":a: 1", "", "ifdef::a[]", "shown", "endif::[]"},
+			want:  []string{"first", "", ":a: 1", "", "shown"},
+			locations: []ast.Location{
+				location(1, 1, 1, 5),
+				location(6, 1, 6, 5),
+			},
+		},
+		{
+			name:  "Skipped lines before the block",
+			input: []string{"ifdef::foo[]", "hidden", "endif::[]", "", "visible para"},
+			want:  []string{"", "visible para"},
+			locations: []ast.Location{
+				location(5, 1, 5, 12),
+			},
+		},
+		{
+			name:  "Escaped",
+			input: []string{`\ifdef::a[]`},
+			want:  []string{"ifdef::a[]"},
+		},
+		{
+			name:  "Mismatched",
+			input: []string{"text", "ifdef::a[]", "endif::b[]", "endif::a[]"},
+			want:  []string{"text"},
+			diagnostics: []Diagnostic{
+				{
+					Line:    2,
+					Message: "ifdef::a[] conditional closed by mismatched endif::b[]",
+				},
+			},
+		},
+		{
+			name:  "Unterminated",
+			input: []string{"text", "ifndef::a[]", "shown"},
+			want:  []string{"text", "shown"},
+			diagnostics: []Diagnostic{
+				{
+					Line:    2,
+					Message: "unterminated ifndef::a[] conditional",
+				},
+			},
+		},
+		{
+			name:  "Without the opening",
+			input: []string{"text", "endif::[]"},
+			want:  []string{"text"},
+			diagnostics: []Diagnostic{
+				{
+					Line:    2,
+					Message: "endif::[] without the opening conditional",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser([]byte(strings.Join(tt.input, "\n")))
+
+			doc := p.parseDocument()
+
+			got := []string{}
+			for _, line := range p.lines {
+				got = append(got, string(line))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDocument() lines = %q, want %q", got, tt.want)
+			}
+
+			if tt.locations != nil {
+				var locations []ast.Location
+				for _, b := range doc.Blocks {
+					locations = append(locations, locationOf(b))
+				}
+
+				if !reflect.DeepEqual(locations, tt.locations) {
+					t.Errorf("parseDocument() block locations = %v, want %v", locations, tt.locations)
+				}
+			}
+
+			if !reflect.DeepEqual(p.diagnostics, tt.diagnostics) {
+				t.Errorf("parseDocument() diagnostics = %v, want %v", p.diagnostics, tt.diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"cmp"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+// Delimited leaf block keeps its content verbatim
+//
+// Pattern: "----" ... "----", the closing delimiter has the same length as the opening one
+func (p *parser) parseDelimitedLeaf(l *line, name ast.Name) *ast.LeafBlock {
+	start := p.lineNum
+
+	p.openVerbatimFence(l)
+
+	for p.nextLine() != nil {
+	}
+
+	last := p.lineNum
+	end := p.lineEnd(last)
+
+	if p.closeFence() {
+		end = p.lineEnd(p.lineNum)
+	} else {
+		p.report(start, "unterminated %s block", name)
+	}
+
+	leaf := &ast.LeafBlock{
+		Name:      name,
+		Form:      ast.DelimitedForm,
+		Delimiter: string(l.content),
+		AbstructBlock: ast.AbstructBlock{
+			Type: ast.BlockType,
+			Location: []ast.LocationBoundary{
+				p.at(start, 1),
+				end,
+			},
+		},
+	}
+
+	for x := start + 1; x <= last; x++ {
+		switch name {
+		case ast.VerseName:
+			leaf.Inlines = append(leaf.Inlines, p.parseInlines(p.lines[x-1], x, 1))
+		case ast.ListingName, ast.LiteralName:
+			content, callouts := p.parseCallouts(p.lines[x-1], x, len(leaf.Callouts))
+			if len(content) > 0 {
+				leaf.Inlines = append(leaf.Inlines, ast.Inlines{p.newText(content, x, 1)})
+			} else {
+				leaf.Inlines = append(leaf.Inlines, nil)
+			}
+			leaf.Callouts = append(leaf.Callouts, callouts...)
+		default:
+			leaf.Inlines = append(leaf.Inlines, p.parseVerbatim(x, 0))
+		}
+	}
+
+	p.callouts = nil
+	for _, c := range leaf.Callouts {
+		p.callouts = append(p.callouts, c.Number)
+	}
+
+	return leaf
+}
+
+// Delimited parent block contains other blocks
+//
+// Pattern: "====" ... "====", nested blocks of the same kind use longer delimiters
+func (p *parser) parseDelimitedParent(l *line, name ast.Name) *ast.ParentBlock {
+	start := p.lineNum
+
+	p.openFence(l)
+
+	// Lists inside the block do not continue the outer ones,
+	// metadata inside the block belongs to its blocks only
+	lists, meta := p.lists, p.meta
+	p.lists, p.meta = nil, nil
+
+	parent := &ast.ParentBlock{
+		Name:      name,
+		Form:      ast.DelimitedForm,
+		Delimiter: string(l.content),
+		Blocks:    p.parseBlocks(),
+		AbstructBlock: ast.AbstructBlock{
+			Type: ast.BlockType,
+		},
+	}
+
+	if p.meta != nil {
+		dangling := p.meta.data.Location[0]
+		p.reportIn(cmp.Or(dangling.File, p.name), dangling.Line, "dropping block metadata without the block")
+	}
+
+	p.lists, p.meta = lists, meta
+
+	if !p.closeFence() {
+		p.report(start, "unterminated %s block", name)
+	}
+
+	parent.Location = []ast.LocationBoundary{
+		p.at(start, 1),
+		p.lineEnd(p.lineNum),
+	}
+
+	return parent
+}
+
+// Block with the "comment" style is dropped with all its content
+//
+// Pattern: "[comment]" followed by the paragraph or the delimited block
+func (p *parser) skipCommentStyled(l *line) {
+	if _, ok := delimiterKind(l.content); ok {
+		p.skipCommentBlock(l)
+		return
+	}
+
+	p.parseParagraph(l)
+}
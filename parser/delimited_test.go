@@ -0,0 +1,450 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func TestParseDelimitedLeafBlocks(t *testing.T) {
+
+	const (
+		Listing      = "Listing"
+		LongerFence  = "Longer fence"
+		Literal      = "Literal"
+		Pass         = "Pass"
+		Stem         = "Stem"
+		Verse        = "Verse"
+		Interrupts   = "Interrupts paragraph"
+		Unterminated = "Unterminated"
+	)
+
+	cases := map[string][]string{
+		Listing: {
+			"----",
+			"func main() {",
+			"",
+			"  return",
+			"}",
+			"----",
+		},
+		LongerFence: {
+			"------",
+			"----",
+			"------",
+		},
+		Literal: {
+			"....",
+			"  kept",
+			"....",
+		},
+		Pass: {
+			"++++",
+			"<b>raw</b>",
+			"++++",
+		},
+		Stem: {
+			"[stem]",
+			"++++",
+			"sqrt(4) = 2",
+			"++++",
+		},
+		Verse: {
+			"[verse]",
+			"____",
+			"The fog comes",
+			"  on little cat feet.",
+			"____",
+		},
+		Interrupts: {
+			"Text",
+			"....",
+			"literal",
+			"....",
+		},
+		Unterminated: {
+			"----",
+			"code",
+		},
+	}
+
+	leaf := func(name ast.Name, delimiter string, loc ast.Location, lines ...ast.Inlines) *ast.LeafBlock {
+		return &ast.LeafBlock{
+			Name:      name,
+			Form:      ast.DelimitedForm,
+			Inlines:   lines,
+			Delimiter: delimiter,
+			AbstructBlock: ast.AbstructBlock{
+				Type:     ast.BlockType,
+				Location: loc,
+			},
+		}
+	}
+
+	styled := func(style string, leaf *ast.LeafBlock) *ast.LeafBlock {
+		leaf.MetaData = styleMeta(style, leaf.Location[0].Line-1)
+		return leaf
+	}
+
+	tests := []struct {
+		name        string
+		input       []byte
+		want        []ast.Block
+		diagnostics []Diagnostic
+	}{
+		{
+			name:  Listing,
+			input: []byte(strings.Join(cases[Listing], "\n")),
+			want: []ast.Block{
+				leaf(ast.ListingName, "----", location(1, 1, 6, 4),
+					ast.Inlines{text("func main() {", 2, 1)},
+					nil,
+					ast.Inlines{text("  return", 4, 1)},
+					ast.Inlines{text("}", 5, 1)},
+				),
+			},
+		},
+		{
+			name:  LongerFence,
+			input: []byte(strings.Join(cases[LongerFence], "\n")),
+			want: []ast.Block{
+				leaf(ast.ListingName, "------", location(1, 1, 3, 6),
+					ast.Inlines{text("----", 2, 1)},
+				),
+			},
+		},
+		{
+			name:  Literal,
+			input: []byte(strings.Join(cases[Literal], "\n")),
+			want: []ast.Block{
+				leaf(ast.LiteralName, "....", location(1, 1, 3, 4),
+					ast.Inlines{text("  kept", 2, 1)},
+				),
+			},
+		},
+		{
+			name:  Pass,
+			input: []byte(strings.Join(cases[Pass], "\n")),
+			want: []ast.Block{
+				leaf(ast.PassName, "++++", location(1, 1, 3, 4),
+					ast.Inlines{text("<b>raw</b>", 2, 1)},
+				),
+			},
+		},
+		{
+			name:  Stem,
+			input: []byte(strings.Join(cases[Stem], "\n")),
+			want: []ast.Block{
+				styled("stem", leaf(ast.StemName, "++++", location(2, 1, 4, 4),
+					ast.Inlines{text("sqrt(4) = 2", 3, 1)},
+				)),
+			},
+		},
+		{
+			name:  Verse,
+			input: []byte(strings.Join(cases[Verse], "\n")),
+			want: []ast.Block{
+				styled("verse", leaf(ast.VerseName, "____", location(2, 1, 5, 4),
+					ast.Inlines{text("The fog comes", 3, 1)},
+					ast.Inlines{text("  on little cat feet.", 4, 1)},
+				)),
+			},
+		},
+		{
+			name:  Interrupts,
+			input: []byte(strings.Join(cases[Interrupts], "\n")),
+			want: []ast.Block{
+				&ast.LeafBlock{
+					Name:    ast.ParagraphName,
+					Form:    ast.ParagraphForm,
+					Inlines: []ast.Inlines{{text("Text", 1, 1)}},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(1, 1, 1, 4),
+					},
+				},
+				leaf(ast.LiteralName, "....", location(2, 1, 4, 4),
+					ast.Inlines{text("literal", 3, 1)},
+				),
+			},
+		},
+		{
+			name:  Unterminated,
+			input: []byte(strings.Join(cases[Unterminated], "\n")),
+			want: []ast.Block{
+				leaf(ast.ListingName, "----", location(1, 1, 2, 4),
+					ast.Inlines{text("code", 2, 1)},
+				),
+			},
+			diagnostics: []Diagnostic{
+				{
+					Line:    1,
+					Message: "unterminated listing block",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+
+			if !reflect.DeepEqual(p.diagnostics, tt.diagnostics) {
+				t.Errorf("parseDocument() diagnostics = %v, want %v", p.diagnostics, tt.diagnostics)
+			}
+		})
+	}
+}
+
+func TestParseDelimitedParentBlocks(t *testing.T) {
+
+	const (
+		Example      = "Example"
+		Nested       = "Nested"
+		Open         = "Open"
+		Quote        = "Quote"
+		NoSections   = "No sections"
+		Comment      = "Comment"
+		Verbatim     = "Outer delimiter in verbatim"
+		Unterminated = "Unterminated"
+		Dangling     = "Dangling metadata"
+	)
+
+	cases := map[string][]string{
+		Example: {
+			"====",
+			"Text",
+			"====",
+		},
+		Nested: {
+			"****",
+			"====",
+			"======",
+			"----",
+			"code",
+			"----",
+			"======",
+			"====",
+			"****",
+		},
+		Open: {
+			"--",
+			"First",
+			"",
+			"Second",
+			"--",
+		},
+		Quote: {
+			"____",
+			"Quoted",
+			"____",
+		},
+		NoSections: {
+			"====",
+			"== Not a section",
+			"====",
+		},
+		Comment: {
+			"[comment]",
+			"--",
+			"Hidden",
+			"--",
+			"Shown",
+		},
+		Verbatim: {
+			"====",
+			"----",
+			"code",
+			"====",
+			"more",
+			"----",
+			"====",
+		},
+		Unterminated: {
+			"****",
+			"Text",
+		},
+		Dangling: {
+			"====",
+			"[source,go]",
+			".Leaked title",
+			"====",
+			"",
+			"Outside para",
+		},
+	}
+
+	paragraph := func(value string, line int) *ast.LeafBlock {
+		return &ast.LeafBlock{
+			Name:    ast.ParagraphName,
+			Form:    ast.ParagraphForm,
+			Inlines: []ast.Inlines{{text(value, line, 1)}},
+			AbstructBlock: ast.AbstructBlock{
+				Type:     ast.BlockType,
+				Location: location(line, 1, line, len(value)),
+			},
+		}
+	}
+
+	parent := func(name ast.Name, delimiter string, loc ast.Location, blocks ...ast.Block) *ast.ParentBlock {
+		return &ast.ParentBlock{
+			Name:      name,
+			Form:      ast.DelimitedForm,
+			Delimiter: delimiter,
+			Blocks:    blocks,
+			AbstructBlock: ast.AbstructBlock{
+				Type:     ast.BlockType,
+				Location: loc,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		input       []byte
+		want        []ast.Block
+		diagnostics []Diagnostic
+	}{
+		{
+			name:  Example,
+			input: []byte(strings.Join(cases[Example], "\n")),
+			want: []ast.Block{
+				parent(ast.ExampleName, "====", location(1, 1, 3, 4),
+					paragraph("Text", 2),
+				),
+			},
+		},
+		{
+			name:  Nested,
+			input: []byte(strings.Join(cases[Nested], "\n")),
+			want: []ast.Block{
+				parent(ast.SidebarName, "****", location(1, 1, 9, 4),
+					parent(ast.ExampleName, "====", location(2, 1, 8, 4),
+						parent(ast.ExampleName, "======", location(3, 1, 7, 6),
+							&ast.LeafBlock{
+								Name:      ast.ListingName,
+								Form:      ast.DelimitedForm,
+								Inlines:   []ast.Inlines{{text("code", 5, 1)}},
+								Delimiter: "----",
+								AbstructBlock: ast.AbstructBlock{
+									Type:     ast.BlockType,
+									Location: location(4, 1, 6, 4),
+								},
+							},
+						),
+					),
+				),
+			},
+		},
+		{
+			name:  Open,
+			input: []byte(strings.Join(cases[Open], "\n")),
+			want: []ast.Block{
+				parent(ast.OpenName, "--", location(1, 1, 5, 2),
+					paragraph("First", 2),
+					paragraph("Second", 4),
+				),
+			},
+		},
+		{
+			name:  Quote,
+			input: []byte(strings.Join(cases[Quote], "\n")),
+			want: []ast.Block{
+				parent(ast.QuoteName, "____", location(1, 1, 3, 4),
+					paragraph("Quoted", 2),
+				),
+			},
+		},
+		{
+			name:  NoSections,
+			input: []byte(strings.Join(cases[NoSections], "\n")),
+			want: []ast.Block{
+				parent(ast.ExampleName, "====", location(1, 1, 3, 4),
+					paragraph("== Not a section", 2),
+				),
+			},
+		},
+		{
+			name:  Comment,
+			input: []byte(strings.Join(cases[Comment], "\n")),
+			want: []ast.Block{
+				paragraph("Shown", 5),
+			},
+		},
+		{
+			name:  Verbatim,
+			input: []byte(strings.Join(cases[Verbatim], "\n")),
+			want: []ast.Block{
+				parent(ast.ExampleName, "====", location(1, 1, 7, 4),
+					&ast.LeafBlock{
+						Name: ast.ListingName,
+						Form: ast.DelimitedForm,
+						Inlines: []ast.Inlines{
+							{text("code", 3, 1)},
+							{text("====", 4, 1)},
+							{text("more", 5, 1)},
+						},
+						Delimiter: "----",
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Location: location(2, 1, 6, 4),
+						},
+					},
+				),
+			},
+		},
+		{
+			name:  Unterminated,
+			input: []byte(strings.Join(cases[Unterminated], "\n")),
+			want: []ast.Block{
+				parent(ast.SidebarName, "****", location(1, 1, 2, 4),
+					paragraph("Text", 2),
+				),
+			},
+			diagnostics: []Diagnostic{
+				{
+					Line:    1,
+					Message: "unterminated sidebar block",
+				},
+			},
+		},
+		{
+			name:  Dangling,
+			input: []byte(strings.Join(cases[Dangling], "\n")),
+			want: []ast.Block{
+				parent(ast.ExampleName, "====", location(1, 1, 4, 4)),
+				paragraph("Outside para", 6),
+			},
+			diagnostics: []Diagnostic{
+				{
+					Line:    2,
+					Message: "dropping block metadata without the block",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+
+			if !reflect.DeepEqual(p.diagnostics, tt.diagnostics) {
+				t.Errorf("parseDocument() diagnostics = %v, want %v", p.diagnostics, tt.diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Diagnostic is a problem found in the document source.
+//
+// Parser reports it and goes on, so the document is still built.
+type Diagnostic struct {
+	File    string // name of the document or the included file, empty if not set
+	Line    int    // line number in File
+	Message string
+}
+
+func (d Diagnostic) Error() string {
+	if d.File != "" {
+		return fmt.Sprintf("%s: line %d: %s", d.File, d.Line, d.Message)
+	}
+
+	return fmt.Sprintf("line %d: %s", d.Line, d.Message)
+}
+
+func (p *parser) report(lineNum int, format string, args ...any) {
+	file, lineNum := p.sourceOf(lineNum)
+
+	p.reportIn(file, lineNum, format, args...)
+}
+
+// Diagnostic of the line of the file, like the directive line dropped by the preprocessor
+func (p *parser) reportIn(file string, lineNum int, format string, args ...any) {
+	p.diagnostics = append(p.diagnostics, Diagnostic{
+		File:    file,
+		Line:    lineNum,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// All reported diagnostics joined into the single error
+func (p *parser) err() error {
+	errs := make([]error, 0, len(p.diagnostics))
+
+	for _, d := range p.diagnostics {
+		errs = append(errs, d)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,310 @@
+package parser
+
+import (
+	"bytes"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var includeRx = regexp.MustCompile(`^include::([^\s\[](?:[^\[]*[^\s\[])?)\[(.*)\]$`)
+
+// Include directive is replaced with the lines of the included file
+//
+// Pattern: "include::target[lines=1..3;5, tag=name, tags=a;!b, leveloffset=+1, indent=2]"
+//
+// Target is resolved relative to the including file. Directive of the missing file
+// is replaced with the "Unresolved directive" line, the recursive one is dropped.
+func (p *parser) include(x int) {
+	lineNum := x + 1
+
+	m := includeRx.FindSubmatch(p.lines[x])
+
+	var (
+		target = string(p.substituteAttributes(m[1], lineNum))
+		attrs  = parseAttributeList(string(m[2]))
+		from   = p.sourceFileOf(lineNum)
+	)
+
+	file := &sourceFile{
+		name:        filepath.Join(filepath.Dir(from.name), filepath.FromSlash(target)),
+		path:        path.Join(path.Dir(from.path), target),
+		levelOffset: from.levelOffset,
+		include:     &source{file: from, num: lineNum},
+	}
+
+	for f := from; ; f = f.include.file {
+		if f.path == file.path {
+			p.report(lineNum, "recursive include of %s", target)
+			p.replaceLine(x, nil, nil)
+			return
+		}
+
+		if f.include == nil {
+			break
+		}
+	}
+
+	var content []byte
+
+	err := fs.ErrNotExist
+	if p.fsys != nil {
+		content, err = fs.ReadFile(p.fsys, file.path)
+	}
+
+	if err != nil {
+		p.report(lineNum, "include file not found: %s", target)
+
+		_, num := p.sourceOf(lineNum)
+
+		unresolved := "Unresolved directive in " + from.name + " - " + string(p.lines[x])
+		p.replaceLine(x, [][]byte{[]byte(unresolved)}, []source{{file: from, num: num}})
+		return
+	}
+
+	lines := splitLines(content)
+
+	// Last line ending is not the empty line
+	if len(lines) > 1 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	// Numbers of the lines in the file
+	nums := make([]int, len(lines))
+	for y := range nums {
+		nums[y] = y + 1
+	}
+
+	switch {
+	case attrs["lines"] != "":
+		lines, nums = selectLines(lines, attrs["lines"])
+	case attrs["tag"] != "" || attrs["tags"] != "":
+		var missing []string
+
+		lines, nums, missing = selectTags(lines, attrs["tag"]+";"+attrs["tags"])
+
+		for _, tag := range missing {
+			p.report(lineNum, "tag not found in include file: %s", tag)
+		}
+	}
+
+	if indent, err := strconv.Atoi(attrs["indent"]); err == nil && indent >= 0 {
+		lines = reindent(lines, indent)
+	}
+
+	if offset := attrs["leveloffset"]; offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err == nil {
+			if offset[0] == '+' || offset[0] == '-' {
+				n += file.levelOffset
+			}
+			file.levelOffset = n
+		}
+	}
+
+	if file.levelOffset != 0 {
+		offsetLevels(lines, file.levelOffset)
+	}
+
+	sources := make([]source, len(lines))
+	for y, num := range nums {
+		sources[y] = source{file: file, num: num}
+	}
+
+	p.replaceLine(x, lines, sources)
+}
+
+// Lines picked by the ranges and their numbers
+//
+// Pattern: "1..3;5;7..-1", ranges are separated by ";" or ",", "-1" or nothing is the last line
+func selectLines(lines [][]byte, spec string) (selected [][]byte, nums []int) {
+	keep := make([]bool, len(lines))
+
+	for _, r := range strings.FieldsFunc(spec, func(r rune) bool { return r == ';' || r == ',' }) {
+		first, last, ok := strings.Cut(strings.TrimSpace(r), "..")
+
+		from, err := strconv.Atoi(first)
+		if err != nil {
+			continue
+		}
+
+		to := from
+		if ok {
+			to = len(lines)
+			if n, err := strconv.Atoi(last); err == nil && n >= 0 {
+				to = n
+			}
+		}
+
+		for num := max(from, 1); num <= min(to, len(lines)); num++ {
+			keep[num-1] = true
+		}
+	}
+
+	for x, line := range lines {
+		if keep[x] {
+			selected = append(selected, line)
+			nums = append(nums, x+1)
+		}
+	}
+
+	return selected, nums
+}
+
+var tagRx = regexp.MustCompile(`\b(tag|end)::(\S+?)\[\](?:\s|$)`)
+
+// Lines of the tagged regions "tag::name[]" ... "end::name[]", the marker lines are dropped
+//
+// Pattern: "a;b", "!b" excludes the region, "*" selects all regions, "**" all lines.
+// Region inside the selected one is selected unless it is excluded.
+//
+// Returns the numbers of the selected lines and the requested tags not found in the lines too.
+func selectTags(lines [][]byte, spec string) (selected [][]byte, nums []int, missing []string) {
+	var (
+		want     = map[string]bool{}
+		wildcard *bool
+		all      *bool
+		positive bool
+	)
+
+	for _, tag := range strings.FieldsFunc(spec, func(r rune) bool { return r == ';' || r == ',' }) {
+		tag = strings.TrimSpace(tag)
+
+		include := !strings.HasPrefix(tag, "!")
+		tag = strings.TrimPrefix(tag, "!")
+
+		switch tag {
+		case "**":
+			all = &include
+		case "*":
+			wildcard = &include
+			positive = positive || include
+		default:
+			want[tag] = include
+			positive = positive || include
+		}
+	}
+
+	// Only excluded regions mean all the other lines
+	base := !positive
+	if all != nil {
+		base = *all
+	}
+
+	var (
+		found = map[string]bool{}
+		open  []string
+	)
+
+	for x, line := range lines {
+		if m := tagRx.FindSubmatch(line); m != nil {
+			tag := string(m[2])
+			found[tag] = true
+
+			switch {
+			case string(m[1]) == "tag":
+				open = append(open, tag)
+			case len(open) > 0 && open[len(open)-1] == tag:
+				open = open[:len(open)-1]
+			}
+
+			continue
+		}
+
+		if tagSelected(open, want, wildcard, base) {
+			selected = append(selected, line)
+			nums = append(nums, x+1)
+		}
+	}
+
+	for tag, include := range want {
+		if include && !found[tag] {
+			missing = append(missing, tag)
+		}
+	}
+	slices.Sort(missing)
+
+	return selected, nums, missing
+}
+
+// Innermost region with the explicit choice decides, then the wildcard
+func tagSelected(open []string, want map[string]bool, wildcard *bool, base bool) bool {
+	for x := len(open) - 1; x >= 0; x-- {
+		if include, ok := want[open[x]]; ok {
+			return include
+		}
+	}
+
+	if len(open) > 0 && wildcard != nil {
+		return *wildcard
+	}
+
+	return base
+}
+
+// Common indent of the lines is replaced with indent spaces
+func reindent(lines [][]byte, indent int) [][]byte {
+	common := -1
+
+	for _, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+
+		n := len(line) - len(bytes.TrimLeft(line, " \t"))
+		if common < 0 || n < common {
+			common = n
+		}
+	}
+
+	prefix := bytes.Repeat([]byte(" "), indent)
+
+	for x, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+
+		lines[x] = append(slices.Clone(prefix), line[common:]...)
+	}
+
+	return lines
+}
+
+var (
+	headingRx       = regexp.MustCompile(`^(=+)([ \t]+\S.*)$`)
+	verbatimFenceRx = regexp.MustCompile(`^(-{4,}|\.{4,}|\+{4,}|/{4,})$`)
+)
+
+// Section titles are moved by offset levels, the level is at least the document title one.
+// Lines of the verbatim blocks are kept.
+func offsetLevels(lines [][]byte, offset int) {
+	var fence []byte
+
+	for x, line := range lines {
+		if verbatimFenceRx.Match(line) {
+			switch {
+			case fence == nil:
+				fence = line
+			case bytes.Equal(fence, line):
+				fence = nil
+			}
+			continue
+		}
+
+		if fence != nil {
+			continue
+		}
+
+		m := headingRx.FindSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		level := max(len(m[1])+offset, 1)
+		lines[x] = append(bytes.Repeat([]byte("="), level), m[2]...)
+	}
+}
@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func TestIncludeDirective(t *testing.T) {
+	fsys := fstest.MapFS{
+		"part.adoc": {Data: []byte("first\nsecond\nthird\n")},
+		"chapters/one.adoc": {Data: []byte(strings.Join([]string{
+			"== One",
+			"include::two.adoc[]",
+		}, "\n"))},
+		"chapters/two.adoc": {Data: []byte("=== Two\n----\n== code\n----\n")},
+		"tagged.rb": {Data: []byte(strings.Join([]string{
+			"require 'a'",
+			"# tag::main[]",
+			"def main",
+			"  # tag::body[]",
+			"  run",
+			"  # end::body[]",
+			"end",
+			"# end::main[]",
+		}, "\n"))},
+		"indented.rb": {Data: []byte("    def a\n      b\n    end")},
+		"loop.adoc":   {Data: []byte("loop\ninclude::loop.adoc[]")},
+	}
+
+	tests := []struct {
+		name        string
+		input       []string
+		want        []string
+		locations   []ast.Location
+		diagnostics []Diagnostic
+	}{
+		{
+			name:  "Whole file",
+			input: []string{"include::part.adoc[]", "after"},
+			want:  []string{"first", "second", "third", "after"},
+		},
+		{
+			name:  "Locations",
+			input: []string{"include::part.adoc[]", "", "after"},
+			want:  []string{"first", "second", "third", "", "after"},
+			locations: []ast.Location{
+				{
+					{Line: 1, Collumn: 1, File: "part.adoc"},
+					{Line: 3, Collumn: 5, File: "part.adoc"},
+				},
+				location(3, 1, 3, 5),
+			},
+		},
+		{
+			name:  "Lines",
+			input: []string{"include::part.adoc[lines=1;3..-1]"},
+			want:  []string{"first", "third"},
+			locations: []ast.Location{
+				{
+					{Line: 1, Collumn: 1, File: "part.adoc"},
+					{Line: 3, Collumn: 5, File: "part.adoc"},
+				},
+			},
+		},
+		{
+			name:  "Quoted lines",
+			input: []string{`include::part.adoc[lines="2..3,1"]`},
+			want:  []string{"first", "second", "third"},
+		},
+		{
+			name:  "Tag",
+			input: []string{"include::tagged.rb[tag=body]"},
+			want:  []string{"  run"},
+			locations: []ast.Location{
+				{
+					{Line: 5, Collumn: 3, File: "tagged.rb"},
+					{Line: 5, Collumn: 5, File: "tagged.rb"},
+				},
+			},
+		},
+		{
+			name:  "Excluded tag",
+			input: []string{"include::tagged.rb[tags=main;!body]"},
+			want:  []string{"def main", "end"},
+		},
+		{
+			name:  "Missing tag",
+			input: []string{"include::tagged.rb[tag=none]"},
+			want:  []string{""},
+			diagnostics: []Diagnostic{
+				{
+					File:    "index.adoc",
+					Line:    1,
+					Message: "tag not found in include file: none",
+				},
+			},
+		},
+		{
+			name:  "Indent",
+			input: []string{"include::indented.rb[indent=2]"},
+			want:  []string{"  def a", "    b", "  end"},
+		},
+		{
+			name:  "Nested relative with level offset",
+			input: []string{"include::chapters/one.adoc[leveloffset=+1]"},
+			want:  []string{"=== One", "==== Two", "----", "== code", "----"},
+		},
+		{
+			name:  "Attribute in target",
+			input: []string{"= Title", ":dir: chapters", "", "include::{dir}/two.adoc[]"},
+			want:  []string{"= Title", ":dir: chapters", "", "=== Two", "----", "== code", "----"},
+		},
+		{
+			name:  "Escaped",
+			input: []string{`\include::part.adoc[]`},
+			want:  []string{"include::part.adoc[]"},
+		},
+		{
+			name:  "Missing file",
+			input: []string{"include::missing.adoc[]"},
+			want:  []string{"Unresolved directive in index.adoc - include::missing.adoc[]"},
+			diagnostics: []Diagnostic{
+				{
+					File:    "index.adoc",
+					Line:    1,
+					Message: "include file not found: missing.adoc",
+				},
+			},
+		},
+		{
+			name:  "Recursive",
+			input: []string{"include::loop.adoc[]"},
+			want:  []string{"loop"},
+			diagnostics: []Diagnostic{
+				{
+					File:    "loop.adoc",
+					Line:    2,
+					Message: "recursive include of loop.adoc",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser([]byte(strings.Join(tt.input, "\n")))
+			p.name = "index.adoc"
+			p.root = "index.adoc"
+			p.fsys = fsys
+
+			doc := p.parseDocument()
+
+			got := []string{}
+			for _, line := range p.lines {
+				got = append(got, string(line))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDocument() lines = %q, want %q", got, tt.want)
+			}
+
+			if tt.locations != nil {
+				var locations []ast.Location
+				for _, b := range doc.Blocks {
+					locations = append(locations, locationOf(b))
+				}
+
+				if !reflect.DeepEqual(locations, tt.locations) {
+					t.Errorf("parseDocument() block locations = %v, want %v", locations, tt.locations)
+				}
+			}
+
+			if !reflect.DeepEqual(p.diagnostics, tt.diagnostics) {
+				t.Errorf("parseDocument() diagnostics = %v, want %v", p.diagnostics, tt.diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package parser
+
+import (
+	"bytes"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+// Title of the document, section or heading line without the leading marker
+//
+// Pattern: "== Section title"
+func (p *parser) parseHeading(l *line) ast.Inlines {
+	marker := bytes.IndexAny(l.content, " \t")
+	title := bytes.TrimLeft(l.content[marker:], " \t")
+	start := len(l.spases) + len(l.content) - len(title)
+
+	return p.parseInlines(title, p.lineNum, start+1)
+}
+
+// Inlines of the text placed on the single line
+//
+// lineNum and col point to the first byte of the text
+func (p *parser) parseInlines(text []byte, lineNum, col int) ast.Inlines {
+	if len(text) == 0 {
+		return nil
+	}
+
+	var (
+		inlines ast.Inlines
+
+		// Text between the inline elements, escaping backslashes are dropped
+		plain []byte
+		start int
+	)
+
+	flush := func(end int) {
+		if len(plain) == 0 {
+			return
+		}
+
+		inlines = append(inlines, &ast.InlineLiteral{
+			Name:  ast.TextName,
+			Type:  ast.StringType,
+			Value: string(plain),
+			Location: []ast.LocationBoundary{
+				p.at(lineNum, col+start),
+				p.at(lineNum, col+end-1),
+			},
+		})
+
+		plain = nil
+	}
+
+	for x := 0; x < len(text); {
+		// Attribute value is the plain text
+		if value, n, ok := p.parseAttributeRef(text, x, lineNum); ok {
+			if len(plain) == 0 {
+				start = x
+			}
+			plain = append(plain, value...)
+			x += n
+			continue
+		}
+
+		if inline, n := p.parseInline(text, x, lineNum, col); inline != nil {
+			flush(x)
+			inlines = append(inlines, inline)
+			x += n
+			continue
+		}
+
+		if len(plain) == 0 {
+			start = x
+		}
+
+		// Escaped element is the plain text, footnotes it defines are dropped
+		if text[x] == '\\' && x+1 < len(text) {
+			if m := attributeRefRx.Find(text[x+1:]); m != nil {
+				plain = append(plain, m...)
+				x += 1 + len(m)
+				continue
+			}
+
+			footnotes, diagnostics := len(p.footnotes), len(p.diagnostics)
+
+			if inline, n := p.parseInline(text, x+1, lineNum, col); inline != nil {
+				p.footnotes = p.footnotes[:footnotes]
+				p.diagnostics = p.diagnostics[:diagnostics]
+
+				plain = append(plain, text[x+1:x+1+n]...)
+				x += 1 + n
+				continue
+			}
+		}
+
+		plain = append(plain, text[x])
+		x++
+	}
+
+	flush(len(text))
+
+	return inlines
+}
+
+// Inline element which starts at text[x]
+//
+// Returns nil if there is no element at x, otherwise the element and its length in bytes
+func (p *parser) parseInline(text []byte, x, lineNum, col int) (ast.Inline, int) {
+	if inline, n := p.parseRef(text, x, lineNum, col); inline != nil {
+		return inline, n
+	}
+
+	if inline, n := p.parseInlineMacro(text, x, lineNum, col); inline != nil {
+		return inline, n
+	}
+
+	if inline, n := p.parseCharRef(text, x, lineNum, col); inline != nil {
+		return inline, n
+	}
+
+	if isSpanMark(text[x]) {
+		return p.parseSpan(text, x, lineNum, col)
+	}
+
+	return nil, 0
+}
+
+// Formatted text enclosed in the marks
+//
+// Pattern: "**text**" anywhere in the text or "*text*" surrounded by non-word characters,
+// the same for "_" emphasis, "`" code and "#" mark
+func (p *parser) parseSpan(text []byte, x, lineNum, col int) (ast.Inline, int) {
+	mark := text[x]
+
+	// Unconstrained pair of marks
+	if x+1 < len(text) && text[x+1] == mark {
+		closing := bytes.Index(text[x+2:], []byte{mark, mark})
+		if closing > 0 {
+			end := x + 2 + closing
+			return p.newSpan(text, x, end+2, 2, ast.UnConstrainedForm, lineNum, col), end + 2 - x
+		}
+	}
+
+	// Constrained mark must follow the non-word character
+	if x > 0 && (isWordByte(text[x-1]) || bytes.IndexByte([]byte(";:}"), text[x-1]) >= 0) {
+		return nil, 0
+	}
+
+	if x+1 >= len(text) || isSpace(text[x+1]) {
+		return nil, 0
+	}
+
+	for y := x + 2; y < len(text); y++ {
+		if text[y] != mark || isSpace(text[y-1]) {
+			continue
+		}
+
+		if y+1 < len(text) && (isWordByte(text[y+1]) || text[y+1] == mark) {
+			continue
+		}
+
+		return p.newSpan(text, x, y+1, 1, ast.ConstrainedForm, lineNum, col), y + 1 - x
+	}
+
+	return nil, 0
+}
+
+// Span of text[start:end] with the marks of markLen bytes on both sides
+func (p *parser) newSpan(text []byte, start, end, markLen int, form ast.Form, lineNum, col int) *ast.InlineSpan {
+	return &ast.InlineSpan{
+		Name:    ast.SpanName,
+		Variant: spanVariant(text[start]),
+		Form:    form,
+		AbstractParentInline: ast.AbstractParentInline{
+			Type:    ast.InlineType,
+			Inlines: p.parseInlines(text[start+markLen:end-markLen], lineNum, col+start+markLen),
+			Location: []ast.LocationBoundary{
+				p.at(lineNum, col+start),
+				p.at(lineNum, col+end-1),
+			},
+		},
+	}
+}
+
+func spanVariant(mark byte) ast.Variant {
+	switch mark {
+	case '*':
+		return ast.StrongVariant
+	case '_':
+		return ast.EmphasisVariant
+	case '`':
+		return ast.CodeVariant
+	}
+
+	return ast.MarkVariant
+}
+
+func isSpanMark(b byte) bool {
+	return b == '*' || b == '_' || b == '`' || b == '#'
+}
+
+// Letters, digits and the underscore, bytes of the multibyte characters are counted as letters
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t'
+}
+
+// Verbatim content of the line with number lineNum without the first indent bytes
+func (p *parser) parseVerbatim(lineNum, indent int) ast.Inlines {
+	content := p.lines[lineNum-1]
+
+	if len(content) <= indent {
+		return nil
+	}
+
+	return ast.Inlines{p.newText(content[indent:], lineNum, indent+1)}
+}
+
+func (p *parser) newText(text []byte, lineNum, col int) *ast.InlineLiteral {
+	return &ast.InlineLiteral{
+		Name:  ast.TextName,
+		Type:  ast.StringType,
+		Value: string(text),
+		Location: []ast.LocationBoundary{
+			p.at(lineNum, col),
+			p.at(lineNum, col+len(text)-1),
+		},
+	}
+}
@@ -0,0 +1,180 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func span(variant ast.Variant, form ast.Form, loc ast.Location, inlines ...ast.Inline) *ast.InlineSpan {
+	return &ast.InlineSpan{
+		Name:    ast.SpanName,
+		Variant: variant,
+		Form:    form,
+		AbstractParentInline: ast.AbstractParentInline{
+			Type:     ast.InlineType,
+			Inlines:  inlines,
+			Location: loc,
+		},
+	}
+}
+
+func TestParseInlineSpans(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want ast.Inlines
+	}{
+		{
+			name: "Plain text",
+			text: "plain text",
+			want: ast.Inlines{text("plain text", 1, 1)},
+		},
+		{
+			name: "Constrained strong",
+			text: "a *bold* word",
+			want: ast.Inlines{
+				text("a ", 1, 1),
+				span(ast.StrongVariant, ast.ConstrainedForm, location(1, 3, 1, 8), text("bold", 1, 4)),
+				text(" word", 1, 9),
+			},
+		},
+		{
+			name: "Unconstrained emphasis",
+			text: "in__word__s",
+			want: ast.Inlines{
+				text("in", 1, 1),
+				span(ast.EmphasisVariant, ast.UnConstrainedForm, location(1, 3, 1, 10), text("word", 1, 5)),
+				text("s", 1, 11),
+			},
+		},
+		{
+			name: "Constrained inside the word",
+			text: "snake_case_name",
+			want: ast.Inlines{text("snake_case_name", 1, 1)},
+		},
+		{
+			name: "Nested",
+			text: "*bold `code` #mark#*",
+			want: ast.Inlines{
+				span(ast.StrongVariant, ast.ConstrainedForm, location(1, 1, 1, 20),
+					text("bold ", 1, 2),
+					span(ast.CodeVariant, ast.ConstrainedForm, location(1, 7, 1, 12), text("code", 1, 8)),
+					text(" ", 1, 13),
+					span(ast.MarkVariant, ast.ConstrainedForm, location(1, 14, 1, 19), text("mark", 1, 15)),
+				),
+			},
+		},
+		{
+			name: "Space after the opening mark",
+			text: "a * b*",
+			want: ast.Inlines{text("a * b*", 1, 1)},
+		},
+		{
+			name: "Escaped mark",
+			text: `\*not bold*`,
+			want: ast.Inlines{
+				&ast.InlineLiteral{
+					Name:     ast.TextName,
+					Type:     ast.StringType,
+					Value:    "*not bold*",
+					Location: location(1, 1, 1, 11),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser([]byte(tt.text))
+
+			if got := p.parseInlines([]byte(tt.text), 1, 1); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInlines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCharRefs(t *testing.T) {
+	charRef := func(value string, loc ast.Location) *ast.InlineLiteral {
+		return &ast.InlineLiteral{
+			Name:     ast.CharRefName,
+			Type:     ast.StringType,
+			Value:    value,
+			Location: loc,
+		}
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want ast.Inlines
+	}{
+		{
+			name: "Named and numeric references",
+			text: "A &amp; B&#8212;C&#x2014;",
+			want: ast.Inlines{
+				text("A ", 1, 1),
+				charRef("&amp;", location(1, 3, 1, 7)),
+				text(" B", 1, 8),
+				charRef("&#8212;", location(1, 10, 1, 16)),
+				text("C", 1, 17),
+				charRef("&#x2014;", location(1, 18, 1, 25)),
+			},
+		},
+		{
+			name: "Replacements",
+			text: "(C) wait... go->",
+			want: ast.Inlines{
+				charRef("&#169;", location(1, 1, 1, 3)),
+				text(" wait", 1, 4),
+				charRef("&#8230;", location(1, 9, 1, 11)),
+				text(" go", 1, 12),
+				charRef("&#8594;", location(1, 15, 1, 16)),
+			},
+		},
+		{
+			name: "Em dash",
+			text: "a--b -- c ---",
+			want: ast.Inlines{
+				text("a", 1, 1),
+				charRef("&#8212;", location(1, 2, 1, 3)),
+				text("b ", 1, 4),
+				charRef("&#8212;", location(1, 6, 1, 7)),
+				text(" c ---", 1, 8),
+			},
+		},
+		{
+			name: "Apostrophe",
+			text: "it's 'quoted'",
+			want: ast.Inlines{
+				text("it", 1, 1),
+				charRef("&#8217;", location(1, 3, 1, 3)),
+				text("s 'quoted'", 1, 4),
+			},
+		},
+		{
+			name: "Escaped replacement",
+			text: `\(C)`,
+			want: ast.Inlines{
+				&ast.InlineLiteral{
+					Name:     ast.TextName,
+					Type:     ast.StringType,
+					Value:    "(C)",
+					Location: location(1, 1, 1, 4),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser([]byte(tt.text))
+
+			if got := p.parseInlines([]byte(tt.text), 1, 1); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInlines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
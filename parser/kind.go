@@ -12,7 +12,25 @@ const (
 	kindSectionTitleL4    Kind = "section title level 4"   // =====
 	kindSectionTitleL5    Kind = "section title level 5"   // ======
 	blockLiteralParagraph Kind = "paragraph block"         // Line start with space
-	lineKindAttribute     Kind = "document attribute line" // line match "^:[a-zA-Z0-9_][-a-zA-Z0-9_]*:"
+	lineKindAttribute     Kind = "document attribute line" // line match "^:!?[a-zA-Z0-9_][-a-zA-Z0-9_]*!?:"
 	lineComment           Kind = "inline comment"          // line like "// .*"
 	lineMultilineComment  Kind = "block comment"           // line like "////"
+	lineBlockAttributes   Kind = "block attribute line"    // line like "[style,attr=value]"
+	lineBlockAnchor       Kind = "block anchor line"       // line like "[[id,reftext]]"
+	lineBlockTitle        Kind = "block title line"        // line like ".Title"
+	kindListingDelimiter  Kind = "listing delimiter"       // ----
+	kindLiteralDelimiter  Kind = "literal delimiter"       // ....
+	kindPassDelimiter     Kind = "passthrough delimiter"   // ++++
+	kindQuoteDelimiter    Kind = "quote delimiter"         // ____
+	kindExampleDelimiter  Kind = "example delimiter"       // ====
+	kindSidebarDelimiter  Kind = "sidebar delimiter"       // ****
+	kindOpenDelimiter     Kind = "open delimiter"          // --
+	kindAdmonition        Kind = "admonition paragraph"    // NOTE: text
+	kindListItem          Kind = "list item"               // * text, . text, 1. text, <1> text
+	lineListContinuation  Kind = "list continuation"       // +
+	kindDListItem         Kind = "description list item"   // term:: text, term;; text
+	kindThematicBreak     Kind = "thematic break"          // ''', ---, ***
+	kindPageBreak         Kind = "page break"              // <<<
+	kindBlockMacro        Kind = "block macro"             // image::target[attrs]
+	kindTableDelimiter    Kind = "table delimiter"         // |===, ,===, :===, !===
 )
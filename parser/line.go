@@ -5,6 +5,22 @@ import (
 	"regexp"
 )
 
+var blockAttributesRx = regexp.MustCompile(`^\[(|[\w.#%{,"'].*)\]$`)
+
+var blockAnchorRx = regexp.MustCompile(`^\[\[([\pL_:][\w:.-]*)(?:, *(.+))?\]\]$`)
+
+var blockTitleRx = regexp.MustCompile(`^\.\.?[^ \t.]`)
+
+var breakRx = regexp.MustCompile(`^(?:'{3,}|<{3,}|---|- - -|\*\*\*|\* \* \*|___|_ _ _)$`)
+
+var listItemRx = regexp.MustCompile(`^(-|\*{1,5}|\.{1,5}|\d+\.|<(?:\d+|\.)>)[ \t]+\S`)
+
+var dlistItemRx = regexp.MustCompile(`^(.*?\S)(:{2,4}|;;)(?:[ \t]+(.*))?$`)
+
+var tableDelimiterRx = regexp.MustCompile(`^[|,:!]={3,}$`)
+
+var admonitionRx = regexp.MustCompile(`^(NOTE|TIP|IMPORTANT|CAUTION|WARNING): `)
+
 type line struct {
 	spases  []byte
 	content []byte
@@ -18,30 +34,78 @@ func (l *line) lineKindOf() Kind {
 		return lineEmpty
 	}
 
-	if bytes.Equal(l.content, []byte("////")) {
-		return lineMultilineComment
+	if len(l.spases) == 0 {
+		if kind, ok := delimiterKind(l.content); ok {
+			return kind
+		}
+
+		if tableDelimiterRx.Match(l.content) {
+			return kindTableDelimiter
+		}
 	}
 
 	if bytes.HasPrefix(l.content, []byte("//")) {
 		return lineComment
 	}
 
+	if len(l.spases) == 0 && breakRx.Match(l.content) {
+		if l.content[0] == '<' {
+			return kindPageBreak
+		}
+		return kindThematicBreak
+	}
+
+	if len(l.spases) == 0 && blockMacroRx.Match(l.content) {
+		return kindBlockMacro
+	}
+
+	if listItemRx.Match(l.content) {
+		return kindListItem
+	}
+
+	if dlistItemRx.Match(l.content) {
+		return kindDListItem
+	}
+
+	if len(l.spases) == 0 && bytes.Equal(l.content, []byte("+")) {
+		return lineListContinuation
+	}
+
 	if len(l.spases) > 0 {
 		if l.content[0] != '.' && l.content[0] != '*' && l.content[0] != '-' {
 			return blockLiteralParagraph
 		}
 	}
 
+	if admonitionRx.Match(l.content) {
+		return kindAdmonition
+	}
+
 	switch l.content[0] {
+	case '[':
+		if len(l.spases) == 0 && blockAttributesRx.Match(l.content) {
+			return lineBlockAttributes
+		}
+		if len(l.spases) == 0 && blockAnchorRx.Match(l.content) {
+			return lineBlockAnchor
+		}
+	case '.':
+		if len(l.spases) == 0 && blockTitleRx.Match(l.content) {
+			return lineBlockTitle
+		}
 	case ':':
-		ok, _ := regexp.Match(`^:[a-zA-Z0-9_][-a-zA-Z0-9_]*:`, l.content)
-		if ok {
+		if attributeEntryRx.Match(l.content) {
 			return lineKindAttribute
 		}
 	case '=', '#':
-		subs := bytes.Fields(l.content)
+		marker := bytes.IndexAny(l.content, " \t")
 
-		switch string(subs[0]) {
+		// Heading marker must be followed by the space or tab and the title text
+		if marker < 0 || len(bytes.TrimSpace(l.content[marker:])) == 0 {
+			return defaultKind
+		}
+
+		switch string(l.content[:marker]) {
 		case "=", "#":
 			return kindDocumentTitle
 		case "==", "##":
@@ -61,3 +125,42 @@ func (l *line) lineKindOf() Kind {
 
 	return defaultKind
 }
+
+// Delimiter line is the same character repeated at least four times or "--"
+//
+// Closing delimiter must have the same length as the opening one
+func delimiterKind(content []byte) (Kind, bool) {
+	// Open block delimiter has the fixed length
+	if bytes.Equal(content, []byte("--")) {
+		return kindOpenDelimiter, true
+	}
+
+	if len(content) < 4 {
+		return "", false
+	}
+
+	for _, r := range content[1:] {
+		if r != content[0] {
+			return "", false
+		}
+	}
+
+	switch content[0] {
+	case '/':
+		return lineMultilineComment, true
+	case '-':
+		return kindListingDelimiter, true
+	case '.':
+		return kindLiteralDelimiter, true
+	case '+':
+		return kindPassDelimiter, true
+	case '_':
+		return kindQuoteDelimiter, true
+	case '=':
+		return kindExampleDelimiter, true
+	case '*':
+		return kindSidebarDelimiter, true
+	}
+
+	return "", false
+}
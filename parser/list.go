@@ -0,0 +1,416 @@
+package parser
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+// List is a group of items with the same marker kind
+//
+// Item with another marker kind starts the nested list,
+// unless the marker kind belongs to one of the parent lists.
+//
+// Pattern: "* item", "- item", ". item", "1. item"
+func (p *parser) parseList(l *line) *ast.List {
+	marker := listMarker(l.content)
+
+	list := &ast.List{
+		Name:    ast.ListName,
+		Marker:  marker,
+		Variant: listVariant(marker),
+		AbstructBlock: ast.AbstructBlock{
+			Type: ast.BlockType,
+		},
+	}
+
+	p.lists = append(p.lists, markerKind(marker))
+	defer func() {
+		p.lists = p.lists[:len(p.lists)-1]
+	}()
+
+	list.Items = append(list.Items, p.parseListItem(l, marker))
+
+	for {
+		line := p.nextLine()
+
+		if line == nil {
+			break
+		}
+
+		if p.kind == lineEmpty || p.kind == lineComment {
+			continue
+		}
+
+		if p.kind != kindListItem || markerKind(listMarker(line.content)) != markerKind(marker) {
+			p.lineNum--
+			break
+		}
+
+		list.Items = append(list.Items, p.parseListItem(line, listMarker(line.content)))
+	}
+
+	list.Location = []ast.LocationBoundary{
+		list.Items[0].Location[0],
+		list.Items[len(list.Items)-1].Location[1],
+	}
+
+	if list.Variant == ast.CalloutVariant {
+		p.checkCalloutList(list)
+	}
+
+	return list
+}
+
+// List item contains the principal text and the attached blocks
+//
+// Principal text ends on the empty line, the next item or the list continuation.
+// Block after the list continuation "+" and the nested list are attached to the item.
+func (p *parser) parseListItem(l *line, marker string) ast.ListItem {
+	var (
+		start = p.at(p.lineNum, len(l.spases)+1)
+		end   = p.lineEnd(p.lineNum)
+	)
+
+	text := bytes.TrimLeft(l.content[len(marker):], " \t")
+
+	item := ast.ListItem{
+		Name: ast.ListItemName,
+		AbstractListItem: ast.AbstractListItem{
+			Marker:    marker,
+			Principal: p.parseInlines(text, p.lineNum, len(l.spases)+len(l.content)-len(text)+1),
+			AbstructBlock: ast.AbstructBlock{
+				Type: ast.BlockType,
+			},
+		},
+	}
+
+	item.Principal, end = p.parsePrincipal(item.Principal, end)
+
+	if blocks := p.parseItemBlocks(); blocks != nil {
+		item.Blocks = blocks
+		end = locationOf(blocks[len(blocks)-1])[1]
+	}
+
+	item.Location = []ast.LocationBoundary{start, end}
+
+	return item
+}
+
+// Description list is a group of items with the same marker
+//
+// Pattern: "term:: text", "term::: text", "term:::: text", "term;; text"
+func (p *parser) parseDescriptionList(l *line) *ast.DescriptionList {
+	_, marker, _ := dlistItemParts(l.content)
+
+	dlist := &ast.DescriptionList{
+		Name:   ast.DListName,
+		Marker: string(marker),
+		AbstructBlock: ast.AbstructBlock{
+			Type: ast.BlockType,
+		},
+	}
+
+	p.lists = append(p.lists, dlist.Marker)
+	defer func() {
+		p.lists = p.lists[:len(p.lists)-1]
+	}()
+
+	dlist.Items = append(dlist.Items, p.parseDescriptionListItem(l))
+
+	for {
+		line := p.nextLine()
+
+		if line == nil {
+			break
+		}
+
+		if p.kind == lineEmpty || p.kind == lineComment {
+			continue
+		}
+
+		if p.kind != kindDListItem || itemMarker(p.kind, line.content) != dlist.Marker {
+			p.lineNum--
+			break
+		}
+
+		dlist.Items = append(dlist.Items, p.parseDescriptionListItem(line))
+	}
+
+	dlist.Location = []ast.LocationBoundary{
+		dlist.Items[0].Location[0],
+		dlist.Items[len(dlist.Items)-1].Location[1],
+	}
+
+	return dlist
+}
+
+// Description list item contains one or more terms, the principal text and the attached blocks
+//
+// Terms placed on the consecutive lines with the same marker belong to the same item.
+// Principal text is placed after the marker or on the next lines.
+func (p *parser) parseDescriptionListItem(l *line) ast.DescriptionListItem {
+	var (
+		start = p.at(p.lineNum, len(l.spases)+1)
+		end   = p.lineEnd(p.lineNum)
+	)
+
+	term, marker, text := dlistItemParts(l.content)
+
+	item := ast.DescriptionListItem{
+		Name: ast.DListItemName,
+		Terms: []ast.Inlines{
+			p.parseInlines(term, p.lineNum, len(l.spases)+1),
+		},
+		AbstractListItem: ast.AbstractListItem{
+			Marker: string(marker),
+			AbstructBlock: ast.AbstructBlock{
+				Type: ast.BlockType,
+			},
+		},
+	}
+
+	// Next terms of the same item
+	for text == nil {
+		line := p.nextLine()
+
+		if line == nil {
+			break
+		}
+
+		if p.kind != kindDListItem || itemMarker(p.kind, line.content) != item.Marker {
+			p.lineNum--
+			break
+		}
+
+		l = line
+		term, _, text = dlistItemParts(l.content)
+		item.Terms = append(item.Terms, p.parseInlines(term, p.lineNum, len(l.spases)+1))
+		end = p.lineEnd(p.lineNum)
+	}
+
+	if text != nil {
+		col := len(l.spases) + len(l.content) - len(text) + 1
+		item.Principal, end = p.parsePrincipal(p.parseInlines(text, p.lineNum, col), p.lineEnd(p.lineNum))
+	} else {
+		// Principal text may start on the next line after empty lines
+		for {
+			line := p.nextLine()
+
+			if line == nil {
+				break
+			}
+
+			if p.kind == lineEmpty || p.kind == lineComment {
+				continue
+			}
+
+			// Indented description is the principal text too
+			if p.kind != kindText && p.kind != blockLiteralParagraph {
+				p.lineNum--
+				break
+			}
+
+			item.Principal = p.parseInlines(line.content, p.lineNum, len(line.spases)+1)
+			item.Principal, end = p.parsePrincipal(item.Principal, p.lineEnd(p.lineNum))
+
+			break
+		}
+	}
+
+	if blocks := p.parseItemBlocks(); blocks != nil {
+		item.Blocks = blocks
+		end = locationOf(blocks[len(blocks)-1])[1]
+	}
+
+	item.Location = []ast.LocationBoundary{start, end}
+
+	return item
+}
+
+// Lines of the principal text following the first one
+func (p *parser) parsePrincipal(principal ast.Inlines, end ast.LocationBoundary) (ast.Inlines, ast.LocationBoundary) {
+	for {
+		line := p.nextLine()
+
+		if line == nil {
+			break
+		}
+
+		if p.kind == lineComment {
+			continue
+		}
+
+		if p.interruptsParagraph(p.kind) {
+			p.lineNum--
+			break
+		}
+
+		end = p.lineEnd(p.lineNum)
+
+		if p.dropsLine(line.content) {
+			continue
+		}
+
+		principal = appendLine(principal, p.parseInlines(line.content, p.lineNum, len(line.spases)+1))
+	}
+
+	return principal, end
+}
+
+// Blocks attached to the list item after its principal text
+//
+// Block after the list continuation "+" and the nested list are attached to the item.
+// Item of the current or the parent list ends the item.
+func (p *parser) parseItemBlocks() []ast.Block {
+	var (
+		blocks []ast.Block
+		blank  bool
+	)
+
+loop:
+	for {
+		line := p.nextLine()
+
+		if line == nil {
+			break
+		}
+
+		switch p.kind {
+		case lineEmpty, lineComment:
+			blank = blank || p.kind == lineEmpty
+		case lineListContinuation:
+			if blank {
+				p.lineNum--
+				break loop
+			}
+
+			if block := p.parseAttachedBlock(); block != nil {
+				blocks = append(blocks, block)
+			}
+		case kindListItem, kindDListItem:
+			if slices.Contains(p.lists, markerKind(itemMarker(p.kind, line.content))) {
+				p.lineNum--
+				break loop
+			}
+
+			if block := p.parseBlock(line); block != nil {
+				blocks = append(blocks, block)
+			}
+		default:
+			p.lineNum--
+			break loop
+		}
+	}
+
+	return blocks
+}
+
+// Block placed right after the list continuation line
+func (p *parser) parseAttachedBlock() ast.Block {
+	for {
+		line := p.nextLine()
+
+		if line == nil {
+			return nil
+		}
+
+		block := p.parseBlock(line)
+
+		// Metadata lines are followed by the block itself
+		if isMetaKind(p.kind) {
+			continue
+		}
+
+		return block
+	}
+}
+
+// Marker of the list item line
+func listMarker(content []byte) string {
+	x := bytes.IndexAny(content, " \t")
+
+	return string(content[:x])
+}
+
+// Marker of the list or description list item line
+func itemMarker(kind Kind, content []byte) string {
+	if kind == kindDListItem {
+		_, marker, _ := dlistItemParts(content)
+		return string(marker)
+	}
+
+	return listMarker(content)
+}
+
+// Term, marker and text of the description list item line
+//
+// text is nil if there is no text after the marker
+func dlistItemParts(content []byte) (term, marker, text []byte) {
+	m := dlistItemRx.FindSubmatchIndex(content)
+
+	term = content[m[2]:m[3]]
+	marker = content[m[4]:m[5]]
+
+	if m[6] >= 0 && m[7] > m[6] {
+		text = content[m[6]:m[7]]
+	}
+
+	return term, marker, text
+}
+
+// Items with markers of the same kind belong to the same list
+//
+// All numbered markers like "1." and "10." have the same kind,
+// so do all callout markers like "<1>" and "<.>"
+func markerKind(marker string) string {
+	if marker[0] >= '0' && marker[0] <= '9' {
+		return "1."
+	}
+
+	if marker[0] == '<' {
+		return "<1>"
+	}
+
+	return marker
+}
+
+func listVariant(marker string) ast.Variant {
+	switch marker[0] {
+	case '*', '-':
+		return ast.UnorderedVariant
+	case '<':
+		return ast.CalloutVariant
+	}
+
+	return ast.OrderedVariant
+}
+
+// Inlines of the next line are joined to the text of the previous one with the line break
+func appendLine(inlines, next ast.Inlines) ast.Inlines {
+	if len(inlines) == 0 || len(next) == 0 {
+		return append(inlines, next...)
+	}
+
+	last, ok := inlines[len(inlines)-1].(*ast.InlineLiteral)
+	first, okNext := next[0].(*ast.InlineLiteral)
+
+	if !ok || !okNext || last.Name != ast.TextName || first.Name != ast.TextName {
+		return append(inlines, next...)
+	}
+
+	joined := &ast.InlineLiteral{
+		Name:  ast.TextName,
+		Type:  ast.StringType,
+		Value: last.Value + "\n" + first.Value,
+		Location: []ast.LocationBoundary{
+			last.Location[0],
+			first.Location[1],
+		},
+	}
+
+	inlines = append(inlines[:len(inlines)-1:len(inlines)-1], joined)
+
+	return append(inlines, next[1:]...)
+}
@@ -0,0 +1,430 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func listItem(marker string, loc ast.Location, principal ast.Inlines, blocks ...ast.Block) ast.ListItem {
+	return ast.ListItem{
+		Name: ast.ListItemName,
+		AbstractListItem: ast.AbstractListItem{
+			Marker:    marker,
+			Principal: principal,
+			Blocks:    blocks,
+			AbstructBlock: ast.AbstructBlock{
+				Type:     ast.BlockType,
+				Location: loc,
+			},
+		},
+	}
+}
+
+func list(marker string, variant ast.Variant, loc ast.Location, items ...ast.ListItem) *ast.List {
+	return &ast.List{
+		Name:    ast.ListName,
+		Marker:  marker,
+		Variant: variant,
+		Items:   items,
+		AbstructBlock: ast.AbstructBlock{
+			Type:     ast.BlockType,
+			Location: loc,
+		},
+	}
+}
+
+func TestParseLists(t *testing.T) {
+
+	const (
+		Unordered    = "Unordered"
+		Nested       = "Nested"
+		Ordered      = "Ordered"
+		Numbered     = "Numbered"
+		MultiLine    = "Multi line principal"
+		Continuation = "Continuation"
+		BlankNested  = "Nested after empty line"
+		EndsList     = "Paragraph ends list"
+	)
+
+	cases := map[string][]string{
+		Unordered: {
+			"* one",
+			"* two",
+		},
+		Nested: {
+			"* one",
+			"** nested",
+			"- dash",
+			"* two",
+		},
+		Ordered: {
+			". one",
+			".. nested",
+			". two",
+		},
+		Numbered: {
+			"1. one",
+			"2. two",
+		},
+		MultiLine: {
+			"- first line",
+			"second line",
+		},
+		Continuation: {
+			"* item",
+			"+",
+			"----",
+			"code",
+			"----",
+			"+",
+			"Attached",
+			"* next",
+		},
+		BlankNested: {
+			"* one",
+			"",
+			"** nested",
+		},
+		EndsList: {
+			"* one",
+			"",
+			"Text",
+		},
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []ast.Block
+	}{
+		{
+			name:  Unordered,
+			input: []byte(strings.Join(cases[Unordered], "\n")),
+			want: []ast.Block{
+				list("*", ast.UnorderedVariant, location(1, 1, 2, 5),
+					listItem("*", location(1, 1, 1, 5), ast.Inlines{text("one", 1, 3)}),
+					listItem("*", location(2, 1, 2, 5), ast.Inlines{text("two", 2, 3)}),
+				),
+			},
+		},
+		{
+			name:  Nested,
+			input: []byte(strings.Join(cases[Nested], "\n")),
+			want: []ast.Block{
+				list("*", ast.UnorderedVariant, location(1, 1, 4, 5),
+					listItem("*", location(1, 1, 3, 6), ast.Inlines{text("one", 1, 3)},
+						list("**", ast.UnorderedVariant, location(2, 1, 3, 6),
+							listItem("**", location(2, 1, 3, 6), ast.Inlines{text("nested", 2, 4)},
+								list("-", ast.UnorderedVariant, location(3, 1, 3, 6),
+									listItem("-", location(3, 1, 3, 6), ast.Inlines{text("dash", 3, 3)}),
+								),
+							),
+						),
+					),
+					listItem("*", location(4, 1, 4, 5), ast.Inlines{text("two", 4, 3)}),
+				),
+			},
+		},
+		{
+			name:  Ordered,
+			input: []byte(strings.Join(cases[Ordered], "\n")),
+			want: []ast.Block{
+				list(".", ast.OrderedVariant, location(1, 1, 3, 5),
+					listItem(".", location(1, 1, 2, 9), ast.Inlines{text("one", 1, 3)},
+						list("..", ast.OrderedVariant, location(2, 1, 2, 9),
+							listItem("..", location(2, 1, 2, 9), ast.Inlines{text("nested", 2, 4)}),
+						),
+					),
+					listItem(".", location(3, 1, 3, 5), ast.Inlines{text("two", 3, 3)}),
+				),
+			},
+		},
+		{
+			name:  Numbered,
+			input: []byte(strings.Join(cases[Numbered], "\n")),
+			want: []ast.Block{
+				list("1.", ast.OrderedVariant, location(1, 1, 2, 6),
+					listItem("1.", location(1, 1, 1, 6), ast.Inlines{text("one", 1, 4)}),
+					listItem("2.", location(2, 1, 2, 6), ast.Inlines{text("two", 2, 4)}),
+				),
+			},
+		},
+		{
+			name:  MultiLine,
+			input: []byte(strings.Join(cases[MultiLine], "\n")),
+			want: []ast.Block{
+				list("-", ast.UnorderedVariant, location(1, 1, 2, 11),
+					listItem("-", location(1, 1, 2, 11), ast.Inlines{
+						&ast.InlineLiteral{
+							Name:     ast.TextName,
+							Type:     ast.StringType,
+							Value:    "first line\nsecond line",
+							Location: location(1, 3, 2, 11),
+						},
+					}),
+				),
+			},
+		},
+		{
+			name:  Continuation,
+			input: []byte(strings.Join(cases[Continuation], "\n")),
+			want: []ast.Block{
+				list("*", ast.UnorderedVariant, location(1, 1, 8, 6),
+					listItem("*", location(1, 1, 7, 8), ast.Inlines{text("item", 1, 3)},
+						&ast.LeafBlock{
+							Name:      ast.ListingName,
+							Form:      ast.DelimitedForm,
+							Inlines:   []ast.Inlines{{text("code", 4, 1)}},
+							Delimiter: "----",
+							AbstructBlock: ast.AbstructBlock{
+								Type:     ast.BlockType,
+								Location: location(3, 1, 5, 4),
+							},
+						},
+						&ast.LeafBlock{
+							Name:    ast.ParagraphName,
+							Form:    ast.ParagraphForm,
+							Inlines: []ast.Inlines{{text("Attached", 7, 1)}},
+							AbstructBlock: ast.AbstructBlock{
+								Type:     ast.BlockType,
+								Location: location(7, 1, 7, 8),
+							},
+						},
+					),
+					listItem("*", location(8, 1, 8, 6), ast.Inlines{text("next", 8, 3)}),
+				),
+			},
+		},
+		{
+			name:  BlankNested,
+			input: []byte(strings.Join(cases[BlankNested], "\n")),
+			want: []ast.Block{
+				list("*", ast.UnorderedVariant, location(1, 1, 3, 9),
+					listItem("*", location(1, 1, 3, 9), ast.Inlines{text("one", 1, 3)},
+						list("**", ast.UnorderedVariant, location(3, 1, 3, 9),
+							listItem("**", location(3, 1, 3, 9), ast.Inlines{text("nested", 3, 4)}),
+						),
+					),
+				),
+			},
+		},
+		{
+			name:  EndsList,
+			input: []byte(strings.Join(cases[EndsList], "\n")),
+			want: []ast.Block{
+				list("*", ast.UnorderedVariant, location(1, 1, 1, 5),
+					listItem("*", location(1, 1, 1, 5), ast.Inlines{text("one", 1, 3)}),
+				),
+				&ast.LeafBlock{
+					Name:    ast.ParagraphName,
+					Form:    ast.ParagraphForm,
+					Inlines: []ast.Inlines{{text("Text", 3, 1)}},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(3, 1, 3, 4),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDescriptionLists(t *testing.T) {
+
+	const (
+		SameLine    = "Same line"
+		NextLine    = "Next line"
+		Markers     = "Markers"
+		MultiTerm   = "Multiple terms"
+		Attached    = "Attached blocks"
+		NestedLevel = "Nested level"
+	)
+
+	cases := map[string][]string{
+		SameLine: {
+			"CPU:: The brain",
+			"RAM:: The memory",
+		},
+		NextLine: {
+			"CPU::",
+			"  The brain",
+		},
+		Markers: {
+			"First;; one",
+		},
+		MultiTerm: {
+			"HTTP::",
+			"HTTPS:: Protocols",
+		},
+		Attached: {
+			"Term:: Text",
+			"* item",
+			"+",
+			"More",
+		},
+		NestedLevel: {
+			"Outer:: Text",
+			"Inner::: Nested",
+		},
+	}
+
+	dlistItem := func(marker string, loc ast.Location, terms []ast.Inlines, principal ast.Inlines, blocks ...ast.Block) ast.DescriptionListItem {
+		return ast.DescriptionListItem{
+			Name:  ast.DListItemName,
+			Terms: terms,
+			AbstractListItem: ast.AbstractListItem{
+				Marker:    marker,
+				Principal: principal,
+				Blocks:    blocks,
+				AbstructBlock: ast.AbstructBlock{
+					Type:     ast.BlockType,
+					Location: loc,
+				},
+			},
+		}
+	}
+
+	dlist := func(marker string, loc ast.Location, items ...ast.DescriptionListItem) *ast.DescriptionList {
+		return &ast.DescriptionList{
+			Name:   ast.DListName,
+			Marker: marker,
+			Items:  items,
+			AbstructBlock: ast.AbstructBlock{
+				Type:     ast.BlockType,
+				Location: loc,
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []ast.Block
+	}{
+		{
+			name:  SameLine,
+			input: []byte(strings.Join(cases[SameLine], "\n")),
+			want: []ast.Block{
+				dlist("::", location(1, 1, 2, 16),
+					dlistItem("::", location(1, 1, 1, 15),
+						[]ast.Inlines{{text("CPU", 1, 1)}},
+						ast.Inlines{text("The brain", 1, 7)},
+					),
+					dlistItem("::", location(2, 1, 2, 16),
+						[]ast.Inlines{{text("RAM", 2, 1)}},
+						ast.Inlines{text("The memory", 2, 7)},
+					),
+				),
+			},
+		},
+		{
+			name:  NextLine,
+			input: []byte(strings.Join(cases[NextLine], "\n")),
+			want: []ast.Block{
+				dlist("::", location(1, 1, 2, 11),
+					dlistItem("::", location(1, 1, 2, 11),
+						[]ast.Inlines{{text("CPU", 1, 1)}},
+						ast.Inlines{text("The brain", 2, 3)},
+					),
+				),
+			},
+		},
+		{
+			name:  Markers,
+			input: []byte(strings.Join(cases[Markers], "\n")),
+			want: []ast.Block{
+				dlist(";;", location(1, 1, 1, 11),
+					dlistItem(";;", location(1, 1, 1, 11),
+						[]ast.Inlines{{text("First", 1, 1)}},
+						ast.Inlines{text("one", 1, 9)},
+					),
+				),
+			},
+		},
+		{
+			name:  MultiTerm,
+			input: []byte(strings.Join(cases[MultiTerm], "\n")),
+			want: []ast.Block{
+				dlist("::", location(1, 1, 2, 17),
+					dlistItem("::", location(1, 1, 2, 17),
+						[]ast.Inlines{
+							{text("HTTP", 1, 1)},
+							{text("HTTPS", 2, 1)},
+						},
+						ast.Inlines{text("Protocols", 2, 9)},
+					),
+				),
+			},
+		},
+		{
+			name:  Attached,
+			input: []byte(strings.Join(cases[Attached], "\n")),
+			want: []ast.Block{
+				dlist("::", location(1, 1, 4, 4),
+					dlistItem("::", location(1, 1, 4, 4),
+						[]ast.Inlines{{text("Term", 1, 1)}},
+						ast.Inlines{text("Text", 1, 8)},
+						list("*", ast.UnorderedVariant, location(2, 1, 4, 4),
+							listItem("*", location(2, 1, 4, 4), ast.Inlines{text("item", 2, 3)},
+								&ast.LeafBlock{
+									Name:    ast.ParagraphName,
+									Form:    ast.ParagraphForm,
+									Inlines: []ast.Inlines{{text("More", 4, 1)}},
+									AbstructBlock: ast.AbstructBlock{
+										Type:     ast.BlockType,
+										Location: location(4, 1, 4, 4),
+									},
+								},
+							),
+						),
+					),
+				),
+			},
+		},
+		{
+			name:  NestedLevel,
+			input: []byte(strings.Join(cases[NestedLevel], "\n")),
+			want: []ast.Block{
+				dlist("::", location(1, 1, 2, 15),
+					dlistItem("::", location(1, 1, 2, 15),
+						[]ast.Inlines{{text("Outer", 1, 1)}},
+						ast.Inlines{text("Text", 1, 9)},
+						dlist(":::", location(2, 1, 2, 15),
+							dlistItem(":::", location(2, 1, 2, 15),
+								[]ast.Inlines{{text("Inner", 2, 1)}},
+								ast.Inlines{text("Nested", 2, 10)},
+							),
+						),
+					),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+var blockMacroRx = regexp.MustCompile(`^(image|video|audio|toc)::(\S*?)\[(.*)\]$`)
+
+// Block macro takes the whole line, attribute references of the target and the attributes are replaced
+//
+// Pattern: "image::target[alt,width,height]", "video::target[]", "audio::target[]", "toc::[]"
+func (p *parser) parseBlockMacro(l *line) *ast.BlockMacro {
+	m := blockMacroRx.FindSubmatch(l.content)
+
+	return &ast.BlockMacro{
+		Name:   ast.Name(m[1]),
+		Form:   ast.MacroForm,
+		Target: string(p.substituteAttributes(m[2], p.lineNum)),
+		AbstructBlock: ast.AbstructBlock{
+			Type: ast.BlockType,
+			MetaData: ast.BlockMetaData{
+				Attributes: parseAttributeList(string(p.substituteAttributes(m[3], p.lineNum))),
+			},
+			Location: []ast.LocationBoundary{
+				p.at(p.lineNum, 1),
+				p.lineEnd(p.lineNum),
+			},
+		},
+	}
+}
+
+var inlineMacroRx = regexp.MustCompile(`^(image|kbd|btn|menu|footnote|pass|stem):([^\s\[]*)\[((?:\\\]|[^\]])*)\]`)
+
+// Inline macro which starts at text[x]
+//
+// Pattern: "image:target[alt]", "kbd:[Ctrl+C]", "btn:[Save]", "menu:File[Save > As]",
+// "footnote:[text]", "footnote:id[text]", "footnote:id[]", "pass:[raw]" or "stem:[formula]"
+func (p *parser) parseInlineMacro(text []byte, x, lineNum, col int) (ast.Inline, int) {
+	// Macro starts the word
+	if x > 0 && isWordByte(text[x-1]) {
+		return nil, 0
+	}
+
+	rest := text[x:]
+
+	m := inlineMacroRx.FindSubmatchIndex(rest)
+	if m == nil {
+		return nil, 0
+	}
+
+	var (
+		name    = string(rest[m[2]:m[3]])
+		target  = string(rest[m[4]:m[5]])
+		content = bytes.ReplaceAll(rest[m[6]:m[7]], []byte(`\]`), []byte("]"))
+
+		contentCol = col + x + m[6]
+	)
+
+	macro := &ast.InlineMacro{
+		Name:   ast.Name(name),
+		Target: target,
+		AbstractParentInline: ast.AbstractParentInline{
+			Type: ast.InlineType,
+			Location: []ast.LocationBoundary{
+				p.at(lineNum, col+x),
+				p.at(lineNum, col+x+m[1]-1),
+			},
+		},
+	}
+
+	switch name {
+	case "image":
+		// "image::" is the block macro
+		if target == "" || target[0] == ':' {
+			return nil, 0
+		}
+		macro.Target = string(p.substituteAttributes(rest[m[4]:m[5]], lineNum))
+		macro.Attributes = parseAttributeList(string(p.substituteAttributes(content, lineNum)))
+	case "kbd":
+		if target != "" {
+			return nil, 0
+		}
+		macro.Items = kbdKeys(content)
+	case "btn":
+		if target != "" {
+			return nil, 0
+		}
+		macro.Inlines = p.parseInlines(content, lineNum, contentCol)
+	case "menu":
+		if target == "" {
+			return nil, 0
+		}
+		for _, item := range strings.Split(string(content), ">") {
+			if item = strings.TrimSpace(item); item != "" {
+				macro.Items = append(macro.Items, item)
+			}
+		}
+	case "stem":
+		if target != "" {
+			return nil, 0
+		}
+		if len(content) > 0 {
+			macro.Inlines = ast.Inlines{p.newText(content, lineNum, contentCol)}
+		}
+	case "pass":
+		// Passthrough text goes to the output as is, the target lists substitutions
+		return &ast.InlineLiteral{
+			Name:     ast.RawName,
+			Type:     ast.StringType,
+			Value:    string(content),
+			Location: macro.Location,
+		}, m[1]
+	case "footnote":
+		p.parseFootnote(macro, content, lineNum, contentCol)
+	}
+
+	return macro, m[1]
+}
+
+// Footnote text is kept in the document footnotes, the macro refers to it by the number
+//
+// Footnote with the id defined before is referred by "footnote:id[]"
+func (p *parser) parseFootnote(macro *ast.InlineMacro, content []byte, lineNum, col int) {
+	if macro.Target != "" {
+		for _, footnote := range p.footnotes {
+			if footnote.Id == macro.Target {
+				macro.Number = footnote.Number
+				return
+			}
+		}
+	}
+
+	if len(content) == 0 {
+		p.report(lineNum, "invalid footnote reference: %s", macro.Target)
+		return
+	}
+
+	macro.Inlines = p.parseInlines(content, lineNum, col)
+	macro.Number = len(p.footnotes) + 1
+
+	p.footnotes = append(p.footnotes, ast.Footnote{
+		Id:       macro.Target,
+		Number:   macro.Number,
+		Inlines:  macro.Inlines,
+		Location: macro.Location,
+	})
+}
+
+// Keys of the keyboard shortcut separated by "+" or ","
+//
+// Separator at the end is the key itself: "Ctrl++"
+func kbdKeys(content []byte) []string {
+	s := strings.TrimSpace(string(content))
+
+	if len(s) == 1 {
+		return []string{s}
+	}
+
+	var last string
+	if strings.HasSuffix(s, "++") || strings.HasSuffix(s, ",,") {
+		last = s[len(s)-1:]
+		s = s[:len(s)-2]
+	}
+
+	var keys []string
+	for _, key := range strings.FieldsFunc(s, func(r rune) bool { return r == '+' || r == ',' }) {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	if last != "" {
+		keys = append(keys, last)
+	}
+
+	return keys
+}
@@ -0,0 +1,273 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func TestParseBlockMacros(t *testing.T) {
+
+	const (
+		Image     = "Image"
+		Video     = "Video"
+		Toc       = "Toc"
+		NotAMacro = "Not a macro"
+		Reference = "Attribute reference"
+	)
+
+	cases := map[string][]string{
+		Image: {
+			"image::images/logo.png[Logo,200,100]",
+		},
+		Video: {
+			"video::video.mp4[width=640,start=60]",
+		},
+		Toc: {
+			"toc::[]",
+		},
+		NotAMacro: {
+			" image::indented.png[]",
+		},
+		Reference: {
+			":imagesdir: images",
+			"",
+			"image::{imagesdir}/a.png[{imagesdir}]",
+		},
+	}
+
+	macro := func(name ast.Name, target string, attrs map[string]string, loc ast.Location) *ast.BlockMacro {
+		return &ast.BlockMacro{
+			Name:   name,
+			Form:   ast.MacroForm,
+			Target: target,
+			AbstructBlock: ast.AbstructBlock{
+				Type: ast.BlockType,
+				MetaData: ast.BlockMetaData{
+					Attributes: attrs,
+				},
+				Location: loc,
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []ast.Block
+	}{
+		{
+			name:  Image,
+			input: []byte(strings.Join(cases[Image], "\n")),
+			want: []ast.Block{
+				macro(ast.ImageName, "images/logo.png", map[string]string{
+					"$1": "Logo",
+					"$2": "200",
+					"$3": "100",
+				}, location(1, 1, 1, 36)),
+			},
+		},
+		{
+			name:  Video,
+			input: []byte(strings.Join(cases[Video], "\n")),
+			want: []ast.Block{
+				macro(ast.VideoName, "video.mp4", map[string]string{
+					"width": "640",
+					"start": "60",
+				}, location(1, 1, 1, 36)),
+			},
+		},
+		{
+			name:  Toc,
+			input: []byte(strings.Join(cases[Toc], "\n")),
+			want: []ast.Block{
+				macro(ast.TocName, "", nil, location(1, 1, 1, 7)),
+			},
+		},
+		{
+			name:  NotAMacro,
+			input: []byte(strings.Join(cases[NotAMacro], "\n")),
+			want: []ast.Block{
+				&ast.LeafBlock{
+					Name:    ast.LiteralName,
+					Form:    ast.IndentedForm,
+					Inlines: []ast.Inlines{{text("image::indented.png[]", 1, 2)}},
+					AbstructBlock: ast.AbstructBlock{
+						Type:     ast.BlockType,
+						Location: location(1, 2, 1, 22),
+					},
+				},
+			},
+		},
+		{
+			name:  Reference,
+			input: []byte(strings.Join(cases[Reference], "\n")),
+			want: []ast.Block{
+				macro(ast.ImageName, "images/a.png", map[string]string{
+					"$1": "images",
+				}, location(3, 1, 3, 37)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInlineMacros(t *testing.T) {
+	macro := func(name ast.Name, target string, loc ast.Location) *ast.InlineMacro {
+		return &ast.InlineMacro{
+			Name:   name,
+			Target: target,
+			AbstractParentInline: ast.AbstractParentInline{
+				Type:     ast.InlineType,
+				Location: loc,
+			},
+		}
+	}
+
+	image := macro(ast.ImageName, "icon.png", location(1, 1, 1, 29))
+	image.Attributes = map[string]string{
+		"$1":    "Icon",
+		"width": "16",
+	}
+
+	imageRef := macro(ast.ImageName, "images/a.png", location(1, 1, 1, 36))
+	imageRef.Attributes = map[string]string{
+		"$1": "images",
+	}
+
+	kbd := macro(ast.KbdName, "", location(1, 1, 1, 12))
+	kbd.Items = []string{"Ctrl", "+"}
+
+	btn := macro(ast.BtnName, "", location(1, 1, 1, 10))
+	btn.Inlines = ast.Inlines{text("Save", 1, 6)}
+
+	menu := macro(ast.MenuName, "File", location(1, 1, 1, 20))
+	menu.Items = []string{"Save", "As"}
+
+	stem := macro(ast.StemName, "", location(1, 1, 1, 14))
+	stem.Inlines = ast.Inlines{text("sqrt(4)", 1, 7)}
+
+	tests := []struct {
+		name string
+		text string
+		want ast.Inlines
+	}{
+		{
+			name: "Image",
+			text: "image:icon.png[Icon,width=16]",
+			want: ast.Inlines{image},
+		},
+		{
+			name: "Image with attribute reference",
+			text: "image:{imagesdir}/a.png[{imagesdir}]",
+			want: ast.Inlines{imageRef},
+		},
+		{
+			name: "Kbd",
+			text: "kbd:[Ctrl++]",
+			want: ast.Inlines{kbd},
+		},
+		{
+			name: "Btn",
+			text: "btn:[Save]",
+			want: ast.Inlines{btn},
+		},
+		{
+			name: "Menu",
+			text: "menu:File[Save > As]",
+			want: ast.Inlines{menu},
+		},
+		{
+			name: "Stem",
+			text: "stem:[sqrt(4)]",
+			want: ast.Inlines{stem},
+		},
+		{
+			name: "Pass",
+			text: "pass:[<u>*raw*</u>]",
+			want: ast.Inlines{
+				&ast.InlineLiteral{
+					Name:     ast.RawName,
+					Type:     ast.StringType,
+					Value:    "<u>*raw*</u>",
+					Location: location(1, 1, 1, 19),
+				},
+			},
+		},
+		{
+			name: "Inside the word",
+			text: "akbd:[A]",
+			want: ast.Inlines{text("akbd:[A]", 1, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser([]byte(tt.text))
+			p.attributes["imagesdir"] = "images"
+
+			if got := p.parseInlines([]byte(tt.text), 1, 1); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInlines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFootnotes(t *testing.T) {
+	input := strings.Join([]string{
+		"Alpha.footnote:[First note] Beta.footnote:disclaimer[Second note]",
+		"Gamma.footnote:disclaimer[] Delta.footnote:missing[]",
+	}, "\n")
+
+	want := []ast.Footnote{
+		{
+			Number:   1,
+			Inlines:  ast.Inlines{text("First note", 1, 17)},
+			Location: location(1, 7, 1, 27),
+		},
+		{
+			Id:       "disclaimer",
+			Number:   2,
+			Inlines:  ast.Inlines{text("Second note", 1, 54)},
+			Location: location(1, 34, 1, 65),
+		},
+	}
+
+	diagnostics := []Diagnostic{
+		{
+			Line:    2,
+			Message: "invalid footnote reference: missing",
+		},
+	}
+
+	p := newParser([]byte(input))
+
+	doc := p.parseDocument()
+
+	if !reflect.DeepEqual(doc.Footnotes, want) {
+		t.Errorf("parseDocument().Footnotes = %v, want %v", doc.Footnotes, want)
+	}
+
+	if !reflect.DeepEqual(p.diagnostics, diagnostics) {
+		t.Errorf("parseDocument() diagnostics = %v, want %v", p.diagnostics, diagnostics)
+	}
+
+	// Reference to the defined footnote takes its number
+	reference := doc.Blocks[0].(*ast.LeafBlock).Inlines[1][1].(*ast.InlineMacro)
+	if reference.Number != 2 {
+		t.Errorf("footnote reference number = %d, want 2", reference.Number)
+	}
+}
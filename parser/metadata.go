@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+// Block metadata collected from the lines before the block
+type blockMeta struct {
+	id      string
+	title   ast.Inlines
+	reftext ast.Inlines
+	style   string
+	data    ast.BlockMetaData
+}
+
+// Block attribute line
+//
+// Pattern: "[style#id.role%option,positional,name=value]"
+//
+// Attributes of the consecutive lines are merged.
+func (p *parser) parseBlockAttributes(l *line) {
+	meta := p.pendingMeta()
+
+	attrs := parseAttributeList(string(l.content[1 : len(l.content)-1]))
+
+	// Shorthand syntax of the first positional attribute
+	if first, ok := attrs["$1"]; ok && strings.ContainsAny(first, "#.%") {
+		x := strings.IndexAny(first, "#.%")
+		style, shorthand := first[:x], first[x:]
+
+		delete(attrs, "$1")
+		if style != "" {
+			attrs["$1"] = style
+		}
+
+		for shorthand != "" {
+			kind := shorthand[0]
+
+			shorthand = shorthand[1:]
+			x = strings.IndexAny(shorthand, "#.%")
+			if x < 0 {
+				x = len(shorthand)
+			}
+
+			value := shorthand[:x]
+			shorthand = shorthand[x:]
+
+			if value == "" {
+				continue
+			}
+
+			switch kind {
+			case '#':
+				meta.id = value
+			case '.':
+				meta.data.Roles = append(meta.data.Roles, value)
+			case '%':
+				meta.data.Options = append(meta.data.Options, value)
+			}
+		}
+	}
+
+	if id, ok := attrs["id"]; ok {
+		meta.id = id
+		delete(attrs, "id")
+	}
+
+	if roles, ok := attrs["role"]; ok {
+		meta.data.Roles = append(meta.data.Roles, strings.Fields(roles)...)
+		delete(attrs, "role")
+	}
+
+	for _, name := range []string{"opts", "options"} {
+		if opts, ok := attrs[name]; ok {
+			for _, opt := range strings.Split(opts, ",") {
+				if opt = strings.TrimSpace(opt); opt != "" {
+					meta.data.Options = append(meta.data.Options, opt)
+				}
+			}
+			delete(attrs, name)
+		}
+	}
+
+	if style, ok := attrs["$1"]; ok {
+		meta.style = style
+	}
+
+	if len(attrs) > 0 {
+		if meta.data.Attributes == nil {
+			meta.data.Attributes = map[string]string{}
+		}
+		maps.Copy(meta.data.Attributes, attrs)
+	}
+
+	p.extendMeta(l)
+}
+
+// Block anchor line sets the id and the reference text of the block
+//
+// Pattern: "[[id]]" or "[[id,reftext]]"
+func (p *parser) parseBlockAnchor(l *line) {
+	meta := p.pendingMeta()
+
+	m := blockAnchorRx.FindSubmatchIndex(l.content)
+
+	meta.id = string(l.content[m[2]:m[3]])
+
+	if m[4] >= 0 {
+		meta.reftext = p.parseInlines(l.content[m[4]:m[5]], p.lineNum, m[4]+1)
+	}
+
+	p.extendMeta(l)
+}
+
+// Block title line
+//
+// Pattern: ".Title"
+func (p *parser) parseBlockTitle(l *line) {
+	meta := p.pendingMeta()
+
+	meta.title = p.parseInlines(l.content[1:], p.lineNum, 2)
+
+	p.extendMeta(l)
+}
+
+func (p *parser) pendingMeta() *blockMeta {
+	if p.meta == nil {
+		p.meta = &blockMeta{}
+	}
+
+	return p.meta
+}
+
+// Metadata location spans all metadata lines of the block
+func (p *parser) extendMeta(l *line) {
+	meta := p.pendingMeta()
+
+	if meta.data.Location == nil {
+		meta.data.Location = []ast.LocationBoundary{
+			p.at(p.lineNum, len(l.spases)+1),
+			p.lineEnd(p.lineNum),
+		}
+		return
+	}
+
+	meta.data.Location[1] = p.lineEnd(p.lineNum)
+}
+
+// Metadata for the block being parsed, the pending metadata is reset
+func (p *parser) takeMeta() *blockMeta {
+	meta := p.meta
+	p.meta = nil
+
+	if meta == nil {
+		return &blockMeta{}
+	}
+
+	return meta
+}
+
+// Put the metadata into the block, attributes of the block itself are kept
+func (m *blockMeta) apply(b *ast.AbstructBlock) {
+	if m.id != "" {
+		b.Id = m.id
+	}
+
+	if m.title != nil {
+		b.Title = m.title
+	}
+
+	if m.reftext != nil {
+		b.RefText = m.reftext
+	}
+
+	if m.data.Attributes != nil {
+		attrs := maps.Clone(m.data.Attributes)
+		maps.Copy(attrs, b.MetaData.Attributes)
+		b.MetaData.Attributes = attrs
+	}
+
+	b.MetaData.Options = append(m.data.Options, b.MetaData.Options...)
+	b.MetaData.Roles = append(m.data.Roles, b.MetaData.Roles...)
+
+	if m.data.Location != nil {
+		b.MetaData.Location = m.data.Location
+	}
+}
+
+func isMetaKind(kind Kind) bool {
+	return kind == lineBlockAttributes || kind == lineBlockAnchor || kind == lineBlockTitle
+}
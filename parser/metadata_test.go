@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func TestParseBlockMetadata(t *testing.T) {
+
+	const (
+		Source    = "Source"
+		Shorthand = "Shorthand"
+		Combined  = "Combined"
+		Named     = "Named"
+		Section   = "Section"
+	)
+
+	cases := map[string][]string{
+		Source: {
+			"[source,go]",
+			"----",
+			"x := 1",
+			"----",
+		},
+		Shorthand: {
+			"[#intro.lead.big%hardbreaks]",
+			"Text",
+		},
+		Combined: {
+			"[[quote-1,First quote]]",
+			".Famous words",
+			"[quote, \"Author, Name\"]",
+			"",
+			"____",
+			"Quoted",
+			"____",
+		},
+		Named: {
+			"[id=main,role=\"a b\",opts=\"x,y\",lang=en]",
+			"Text",
+		},
+		Section: {
+			"[[start]]",
+			"== Section",
+		},
+	}
+
+	paragraph := func(value string, line int, meta ast.AbstructBlock) *ast.LeafBlock {
+		meta.Type = ast.BlockType
+		meta.Location = location(line, 1, line, len(value))
+
+		return &ast.LeafBlock{
+			Name:          ast.ParagraphName,
+			Form:          ast.ParagraphForm,
+			Inlines:       []ast.Inlines{{text(value, line, 1)}},
+			AbstructBlock: meta,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  []ast.Block
+	}{
+		{
+			name:  Source,
+			input: []byte(strings.Join(cases[Source], "\n")),
+			want: []ast.Block{
+				&ast.LeafBlock{
+					Name:      ast.ListingName,
+					Form:      ast.DelimitedForm,
+					Inlines:   []ast.Inlines{{text("x := 1", 3, 1)}},
+					Delimiter: "----",
+					AbstructBlock: ast.AbstructBlock{
+						Type: ast.BlockType,
+						MetaData: ast.BlockMetaData{
+							Attributes: map[string]string{
+								"$1": "source",
+								"$2": "go",
+							},
+							Location: location(1, 1, 1, 11),
+						},
+						Location: location(2, 1, 4, 4),
+					},
+				},
+			},
+		},
+		{
+			name:  Shorthand,
+			input: []byte(strings.Join(cases[Shorthand], "\n")),
+			want: []ast.Block{
+				paragraph("Text", 2, ast.AbstructBlock{
+					Id: "intro",
+					MetaData: ast.BlockMetaData{
+						Options:  []string{"hardbreaks"},
+						Roles:    []string{"lead", "big"},
+						Location: location(1, 1, 1, 28),
+					},
+				}),
+			},
+		},
+		{
+			name:  Combined,
+			input: []byte(strings.Join(cases[Combined], "\n")),
+			want: []ast.Block{
+				&ast.ParentBlock{
+					Name:      ast.QuoteName,
+					Form:      ast.DelimitedForm,
+					Delimiter: "____",
+					Blocks: []ast.Block{
+						paragraph("Quoted", 6, ast.AbstructBlock{}),
+					},
+					AbstructBlock: ast.AbstructBlock{
+						Type:    ast.BlockType,
+						Id:      "quote-1",
+						Title:   ast.Inlines{text("Famous words", 2, 2)},
+						RefText: ast.Inlines{text("First quote", 1, 11)},
+						MetaData: ast.BlockMetaData{
+							Attributes: map[string]string{
+								"$1": "quote",
+								"$2": "Author, Name",
+							},
+							Location: location(1, 1, 3, 23),
+						},
+						Location: location(5, 1, 7, 4),
+					},
+				},
+			},
+		},
+		{
+			name:  Named,
+			input: []byte(strings.Join(cases[Named], "\n")),
+			want: []ast.Block{
+				paragraph("Text", 2, ast.AbstructBlock{
+					Id: "main",
+					MetaData: ast.BlockMetaData{
+						Attributes: map[string]string{
+							"lang": "en",
+						},
+						Options:  []string{"x", "y"},
+						Roles:    []string{"a", "b"},
+						Location: location(1, 1, 1, 39),
+					},
+				}),
+			},
+		},
+		{
+			name:  Section,
+			input: []byte(strings.Join(cases[Section], "\n")),
+			want: []ast.Block{
+				&ast.Section{
+					Name: ast.SectionName,
+					AbstractHeading: ast.AbstractHeading{
+						Level: 1,
+						AbstructBlock: ast.AbstructBlock{
+							Type:  ast.BlockType,
+							Id:    "start",
+							Title: ast.Inlines{text("Section", 2, 4)},
+							MetaData: ast.BlockMetaData{
+								Location: location(1, 1, 1, 9),
+							},
+							Location: location(2, 1, 2, 10),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+		})
+	}
+}
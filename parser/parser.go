@@ -2,42 +2,161 @@ package parser
 
 import (
 	"bytes"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
 )
 
+// Options of the document parsing
+type Options struct {
+	// Name of the document in the diagnostics, like the file path
+	Name string
+
+	// Resolver of the include directives, targets are resolved relative to the including file.
+	//
+	// Document itself is Name in FS.
+	FS fs.FS
+
+	// Attributes set from outside of the document, like "asciidoctor -a name=value".
+	//
+	// Attribute is locked, the document can not change it.
+	// Value or name ending with "@" is soft set, the document may override it.
+	// Name ending with "!" unsets the attribute.
+	Attributes map[string]string
+}
+
+// Parse reads and parses the document at path.
+//
+// Problems in the document source are returned as joined Diagnostic errors
+// together with the parsed document.
 func Parse(path string) (*ast.Document, error) {
-	_, err := os.Stat(path)
+	return ParseWithOptions(path, Options{})
+}
+
+// ParseWithOptions reads and parses the document at path like Parse does.
+//
+// Path is the document name unless opts.Name is set.
+// Files are included from the directory of the document unless opts.FS is set,
+// then the document is opts.Name in opts.FS like for ParseBytes.
+func ParseWithOptions(path string, opts Options) (*ast.Document, error) {
+	content, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	content, err := os.ReadFile(path)
+	if opts.Name == "" {
+		opts.Name = path
+	}
+
+	if opts.FS == nil {
+		opts.FS = os.DirFS(filepath.Dir(path))
+		return parse(content, opts, filepath.Base(path))
+	}
+
+	return ParseBytes(content, opts)
+}
+
+// ParseReader parses the document read from r until EOF.
+func ParseReader(r io.Reader, opts Options) (*ast.Document, error) {
+	content, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
+	return ParseBytes(content, opts)
+}
+
+// ParseString parses the document source s.
+func ParseString(s string, opts Options) (*ast.Document, error) {
+	return ParseBytes([]byte(s), opts)
+}
+
+// ParseBytes parses the document source content.
+//
+// Problems in the document source are returned as joined Diagnostic errors
+// together with the parsed document.
+func ParseBytes(content []byte, opts Options) (*ast.Document, error) {
+	return parse(content, opts, filepath.ToSlash(opts.Name))
+}
+
+// Document at root of opts.FS
+func parse(content []byte, opts Options, root string) (*ast.Document, error) {
 	p := newParser(content)
+	p.name = opts.Name
+	p.fsys = opts.FS
+	p.root = root
+	p.setExternalAttributes(opts.Attributes)
 
 	document := p.parseDocument()
 
-	return document, nil
+	return document, p.err()
 }
 
 type parser struct {
-	lines    [][]byte
+	// Name of the document in the diagnostics
+	name string
+
+	lines [][]byte
+	// Lines of the document before the first line, the AsciiDoc table cell is parsed apart
+	offset int
+	// Columns before the line text, the text of the AsciiDoc table cell starts inside the line
+	cols     []int
 	lineNum  int
 	prevKind Kind
 	kind     Kind
+
+	// Delimiters of the open blocks, innermost last
+	fences []fence
+	// Metadata from the lines before the next block
+	meta *blockMeta
+	// Marker kinds of the lists being parsed, innermost last
+	lists []string
+	// Callout numbers of the verbatim blocks before the callout list
+	callouts []int
+	// Footnotes of the document in the order of appearance
+	footnotes []ast.Footnote
+	// Attributes defined at the current line
+	attributes map[string]string
+	// Attributes set from outside of the document
+	external map[string]string
+	// Attributes the document can not change
+	locked map[string]bool
+
+	// Resolver of the include directives
+	fsys fs.FS
+	// Path of the document in fsys
+	root string
+	// Sources of the lines, nil until the first include
+	sources []source
+	// Lines before this one are preprocessed
+	processed int
+	// Conditional directives waiting for endif, innermost last
+	conditionals []conditional
+
+	diagnostics []Diagnostic
 }
 
 func newParser(content []byte) *parser {
-	p := &parser{}
+	p := &parser{
+		attributes: initialAttributes(),
+		external:   map[string]string{},
+		locked:     map[string]bool{},
+	}
+
+	p.lines = splitLines(content)
 
+	return p
+}
+
+// Lines of the source without the trailing spaces
+func splitLines(content []byte) [][]byte {
 	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
-	p.lines = bytes.Split(content, []byte("\n"))
+	lines := bytes.Split(content, []byte("\n"))
 
 	var (
 		wspaces = "\t\n\v\f\r \x85\xA0"
@@ -45,28 +164,32 @@ func newParser(content []byte) *parser {
 		line []byte
 		x    int
 	)
-	for x, line = range p.lines {
-		p.lines[x] = bytes.TrimRight(line, wspaces)
+	for x, line = range lines {
+		lines[x] = bytes.TrimRight(line, wspaces)
 	}
 
-	return p
+	return lines
 }
 
 func (p *parser) parseDocument() *ast.Document {
 
 	doc := ast.NewDocument()
 
-	doc.Location = append(doc.Location, ast.LocationBoundary{
-		Line:    1,
-		Collumn: 1,
-	})
+	doc.Location = append(doc.Location, p.at(1, 1))
+
+	maps.Copy(doc.Attributes, p.external)
 
 	p.parseHeader(doc)
 
-	doc.Location = append(doc.Location, ast.LocationBoundary{
-		Line:    len(p.lines),
-		Collumn: len(p.lines[len(p.lines)-1]),
-	})
+	doc.Blocks = p.parseSectionBlocks(-1)
+	doc.Footnotes = p.footnotes
+
+	// Document of the empty included files is the single empty line
+	if len(p.lines) == 0 {
+		p.lines = [][]byte{nil}
+	}
+
+	doc.Location = append(doc.Location, p.at(len(p.lines), len(p.lines[len(p.lines)-1])))
 
 	return doc
 }
@@ -96,25 +219,26 @@ func (p *parser) parseHeader(doc *ast.Document) {
 
 		switch p.kind {
 		case kindDocumentTitle:
-			clearTitle := bytes.TrimLeft(line.content[1:], " ")
-			start := bytes.Index(line.content, clearTitle)
-			title := &ast.InlineLiteral{
-				Name:  ast.TextName,
-				Type:  ast.StringType,
-				Value: string(clearTitle),
-				Location: []ast.LocationBoundary{
-					{
-						Line:    p.lineNum,
-						Collumn: start + 1,
-					},
-					{
-						Line:    p.lineNum,
-						Collumn: start + len(clearTitle),
-					},
-				},
+			// Only the first title belongs to the header, the next one starts the body
+			if doc.Header.Title != nil {
+				p.lineNum--
+				return
 			}
-			doc.Header.Title = append(doc.Header.Title, title)
+			doc.Header.Title = p.parseHeading(line)
+			p.attributes["doctitle"] = inlinesText(doc.Header.Title)
 		case kindText:
+			// Text before the title means the document has no header
+			if doc.Header.Title == nil {
+				p.lineNum--
+				return
+			}
+
+			// Authors line follows the title immediately, other text starts the body
+			if p.prevKind != kindDocumentTitle {
+				p.lineNum--
+				return
+			}
+
 			authors := bytes.Split(line.content, []byte(";"))
 			for _, author := range authors {
 				var full, in, fn, mn, ln, addr string
@@ -156,63 +280,75 @@ func (p *parser) parseHeader(doc *ast.Document) {
 				})
 			}
 		case lineKindAttribute:
-			k, v := parseDocumentAttribute(line.content)
-			doc.Attributes[k] = v
+			entry, applied := p.parseAttributeEntry(line)
+			doc.Header.AttributeEntries = append(doc.Header.AttributeEntries, entry)
+
+			// Document attributes keep the values set in the header
+			switch {
+			case !applied:
+			case entry.Unset:
+				delete(doc.Attributes, entry.Name)
+			default:
+				doc.Attributes[entry.Name] = entry.Value
+			}
+		case lineComment:
+			continue
+		case lineMultilineComment:
+			p.skipCommentBlock(line)
+		case lineEmpty:
+			// Header ends on the first empty line after the title
+			if doc.Header.Title != nil {
+				return
+			}
+		default:
+			p.lineNum--
+			return
 		}
 	}
 }
 
 func (p *parser) skipEmptyOrCommentLines() {
-	var (
-		isMLC bool
-	)
-
 	for {
 		line := p.nextLine()
 
 		if line == nil {
-			break
+			return
 		}
 
 		switch p.kind {
-		case lineComment:
+		case lineComment, lineEmpty:
 			continue
 		case lineMultilineComment:
-			isMLC = true
-			continue
-		case lineEmpty:
+			p.skipCommentBlock(line)
 			continue
 		}
 
-		if isMLC {
-			continue
-		}
+		p.lineNum--
 
-		break
+		return
 	}
-
-	p.lineNum--
 }
 
-// Not safe document attribute parser
-//
-// Put here only ":key: value" lines
-func parseDocumentAttribute(line []byte) (key string, value string) {
-	attr := bytes.SplitN(line, []byte(" "), 2)
+// Skip lines up to the closing delimiter of the block comment
+func (p *parser) skipCommentBlock(l *line) {
+	start := p.lineNum
 
-	key = string(bytes.Trim(attr[0], ":"))
+	p.openVerbatimFence(l)
 
-	if len(attr) == 2 {
-		value = string(attr[1])
+	for p.nextLine() != nil {
 	}
 
-	return key, value
+	if !p.closeFence() {
+		p.report(start, "unterminated comment block")
+	}
 }
 
 func (p *parser) nextLine() *line {
 	p.prevKind = p.kind
 
-	if p.lineNum >= len(p.lines) {
+	p.preprocess()
+
+	if p.lineNum >= len(p.lines) || p.atFence() {
 		return nil
 	}
 
@@ -239,3 +375,54 @@ func (p *parser) nextLine() *line {
 
 	return line
 }
+
+// Delimiter of the open block
+type fence struct {
+	delimiter string
+	// Content is verbatim, only the own delimiter closes the block
+	verbatim bool
+}
+
+// Lines of the block are read up to its closing delimiter.
+// Delimiter of any open block stops nextLine, so inner blocks
+// can not run past the end of the outer one.
+func (p *parser) openFence(l *line) {
+	p.fences = append(p.fences, fence{delimiter: string(l.content)})
+}
+
+// Lines of the verbatim block are read up to its own closing delimiter,
+// delimiters of the outer blocks are the content
+func (p *parser) openVerbatimFence(l *line) {
+	p.fences = append(p.fences, fence{delimiter: string(l.content), verbatim: true})
+}
+
+// Consume the closing delimiter of the innermost block
+//
+// Returns false if the block is not closed
+func (p *parser) closeFence() bool {
+	fence := p.fences[len(p.fences)-1]
+	p.fences = p.fences[:len(p.fences)-1]
+
+	if p.lineNum < len(p.lines) && string(p.lines[p.lineNum]) == fence.delimiter {
+		p.lineNum++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) atFence() bool {
+	content := string(p.lines[p.lineNum])
+
+	if n := len(p.fences); n > 0 && p.fences[n-1].verbatim {
+		return content == p.fences[n-1].delimiter
+	}
+
+	for _, fence := range p.fences {
+		if content == fence.delimiter {
+			return true
+		}
+	}
+
+	return false
+}
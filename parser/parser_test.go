@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -18,6 +20,7 @@ func TestParseHeader(t *testing.T) {
 		TitleAuthors          = "Title and Authors"
 		TitleAttribute        = "Title and Attribute"
 		TitleAttributes       = "Title and Attributes"
+		TitleStrayText        = "Title and Stray Text"
 	)
 
 	cases := map[string][]string{
@@ -60,6 +63,16 @@ func TestParseHeader(t *testing.T) {
 			"\n",
 			":bool-attr:",
 		},
+		TitleStrayText: {
+			"= T",
+			"\n",
+			":a: b",
+			"\n",
+			"stray text",
+			"\n",
+			"\n",
+			"body",
+		},
 	}
 
 	tests := []struct {
@@ -321,6 +334,13 @@ func TestParseHeader(t *testing.T) {
 							},
 						},
 					},
+					AttributeEntries: []ast.AttributeEntry{
+						{
+							Name:     "nickname",
+							Value:    "mynameisglebushka",
+							Location: location(2, 1, 2, 28),
+						},
+					},
 				},
 				Attributes: map[string]string{
 					"nickname": "mynameisglebushka",
@@ -363,6 +383,17 @@ func TestParseHeader(t *testing.T) {
 							},
 						},
 					},
+					AttributeEntries: []ast.AttributeEntry{
+						{
+							Name:     "nickname",
+							Value:    "mynameisglebushka",
+							Location: location(2, 1, 2, 28),
+						},
+						{
+							Name:     "bool-attr",
+							Location: location(3, 1, 3, 11),
+						},
+					},
 				},
 				Attributes: map[string]string{
 					"nickname":  "mynameisglebushka",
@@ -381,6 +412,50 @@ func TestParseHeader(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: TitleStrayText,
+			input: []byte(
+				strings.Join(cases[TitleStrayText], ""),
+			),
+			want: &ast.Document{
+				Type: ast.BlockType,
+				Name: ast.DocumentName,
+				Header: &ast.Header{
+					Title: []ast.Inline{text("T", 1, 3)},
+					AttributeEntries: []ast.AttributeEntry{
+						{
+							Name:     "a",
+							Value:    "b",
+							Location: location(2, 1, 2, 5),
+						},
+					},
+				},
+				Attributes: map[string]string{
+					"a": "b",
+				},
+				Blocks: []ast.Block{
+					&ast.LeafBlock{
+						Name:    ast.ParagraphName,
+						Form:    ast.ParagraphForm,
+						Inlines: []ast.Inlines{{text("stray text", 3, 1)}},
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Location: location(3, 1, 3, 10),
+						},
+					},
+					&ast.LeafBlock{
+						Name:    ast.ParagraphName,
+						Form:    ast.ParagraphForm,
+						Inlines: []ast.Inlines{{text("body", 5, 1)}},
+						AbstructBlock: ast.AbstructBlock{
+							Type:     ast.BlockType,
+							Location: location(5, 1, 5, 4),
+						},
+					},
+				},
+				Location: location(1, 1, 5, 4),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -413,3 +488,76 @@ func TestSome(t *testing.T) {
 	b := []string{"a"}
 	t.Log(len(b[:len(b)-1]))
 }
+
+func TestParseEntryPoints(t *testing.T) {
+	const source = "= Title\n\n|===\n|a"
+
+	want := "doc.adoc: line 3: unterminated table block"
+
+	parse := map[string]func() (*ast.Document, error){
+		"ParseBytes": func() (*ast.Document, error) {
+			return ParseBytes([]byte(source), Options{Name: "doc.adoc"})
+		},
+		"ParseString": func() (*ast.Document, error) {
+			return ParseString(source, Options{Name: "doc.adoc"})
+		},
+		"ParseReader": func() (*ast.Document, error) {
+			return ParseReader(strings.NewReader(source), Options{Name: "doc.adoc"})
+		},
+	}
+
+	for name, parse := range parse {
+		t.Run(name, func(t *testing.T) {
+			doc, err := parse()
+
+			if doc == nil || len(doc.Blocks) != 1 {
+				t.Fatalf("%s() document = %v, want the single table", name, doc)
+			}
+
+			if err == nil || err.Error() != want {
+				t.Errorf("%s() error = %v, want %v", name, err, want)
+			}
+		})
+	}
+}
+
+func TestParseWithOptionsIncludes(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"index.adoc":        "include::chapters/one.adoc[]",
+		"chapters/one.adoc": "One",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(dir, "index.adoc")
+
+	opts := map[string]Options{
+		"Directory of the document": {},
+		"Name in FS":                {Name: "index.adoc", FS: os.DirFS(dir)},
+	}
+
+	for name, opts := range opts {
+		t.Run(name, func(t *testing.T) {
+			doc, err := ParseWithOptions(path, opts)
+
+			if err != nil {
+				t.Fatalf("ParseWithOptions() error = %v, want nil", err)
+			}
+
+			if len(doc.Blocks) != 1 {
+				t.Errorf("ParseWithOptions().Blocks = %v, want the included paragraph", doc.Blocks)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"bytes"
+	"slices"
+)
+
+// File the document lines come from
+type sourceFile struct {
+	name string // name in the diagnostics
+	path string // path in the include resolver
+
+	// Offset of the section levels
+	levelOffset int
+	// Include directive of the file, nil for the document itself
+	include *source
+}
+
+// Source of the document line
+type source struct {
+	file *sourceFile
+	num  int
+}
+
+// Directives of the line are expanded when the line is read the first time,
+// so they see the attributes defined above them
+func (p *parser) preprocess() {
+	for p.processed == p.lineNum && p.processed < len(p.lines) {
+		content := p.lines[p.processed]
+
+		switch {
+		case conditionalRx.Match(content):
+			// Directive line is dropped or replaced with its text
+			p.conditional(p.processed)
+			continue
+		case p.skipping():
+			p.replaceLine(p.processed, nil, nil)
+			continue
+		case bytes.HasPrefix(content, []byte(`\`)) && (includeRx.Match(content[1:]) || conditionalRx.Match(content[1:])):
+			// Escaped directive is the text
+			p.lines[p.processed] = content[1:]
+		case includeRx.Match(content):
+			// Included lines are preprocessed too
+			p.include(p.processed)
+			continue
+		}
+
+		p.processed++
+	}
+
+	if p.processed == len(p.lines) {
+		for _, c := range p.conditionals {
+			p.reportIn(c.file, c.num, "unterminated %s conditional", c.directive)
+		}
+		p.conditionals = nil
+	}
+}
+
+// Lines with their sources replace the line x, they are not preprocessed yet
+func (p *parser) replaceLine(x int, lines [][]byte, sources []source) {
+	if p.sources == nil {
+		root := &sourceFile{
+			name: p.name,
+			path: p.root,
+		}
+
+		p.sources = make([]source, len(p.lines))
+		for i := range p.sources {
+			p.sources[i] = source{file: root, num: i + 1}
+		}
+	}
+
+	p.lines = slices.Replace(p.lines, x, x+1, lines...)
+	p.sources = slices.Replace(p.sources, x, x+1, sources...)
+}
+
+// File of the line lineNum, the document itself before the first include
+func (p *parser) sourceFileOf(lineNum int) *sourceFile {
+	if p.sources == nil {
+		return &sourceFile{
+			name: p.name,
+			path: p.root,
+		}
+	}
+
+	return p.sources[lineNum-1].file
+}
+
+// Name of the file and the line number in it for the line lineNum of the document
+func (p *parser) sourceOf(lineNum int) (string, int) {
+	lineNum += p.offset
+
+	if lineNum < 1 || lineNum > len(p.sources) {
+		return p.name, lineNum
+	}
+
+	s := p.sources[lineNum-1]
+
+	return s.file.name, s.num
+}
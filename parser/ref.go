@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+var urlRx = regexp.MustCompile(`^(?:https?|ftp|irc)://[^\s\[\]<>"]+(?:\[([^\]]*)\])?`)
+
+var angleURLRx = regexp.MustCompile(`^<((?:https?|ftp|irc)://[^\s<>]+)>`)
+
+var linkMacroRx = regexp.MustCompile(`^(link|mailto):([^\s\[]+)\[([^\]]*)\]`)
+
+var xrefRx = regexp.MustCompile(`^<<([^\s,>]+)(?:, *([^>]*))?>>`)
+
+var xrefMacroRx = regexp.MustCompile(`^xref:([^\s\[]+)\[([^\]]*)\]`)
+
+// Link or cross reference which starts at text[x]
+//
+// Pattern: "https://url", "https://url[text]", "<https://url>", "link:url[text]",
+// "mailto:address[text]", "<<id>>", "<<id,text>>" or "xref:file.adoc#id[text]"
+func (p *parser) parseRef(text []byte, x, lineNum, col int) (ast.Inline, int) {
+	rest := text[x:]
+
+	// Text of the reference from the submatch of rest,
+	// the target rest[from:to] is shown if there is no text
+	refText := func(m []int, group, from, to int) ast.Inlines {
+		if m[group] >= 0 && m[group] < m[group+1] {
+			return p.parseInlines(rest[m[group]:m[group+1]], lineNum, col+x+m[group])
+		}
+
+		if from == to {
+			return nil
+		}
+
+		return ast.Inlines{
+			&ast.InlineLiteral{
+				Name:  ast.TextName,
+				Type:  ast.StringType,
+				Value: string(p.substituteAttributes(rest[from:to], lineNum)),
+				Location: []ast.LocationBoundary{
+					p.at(lineNum, col+x+from),
+					p.at(lineNum, col+x+to-1),
+				},
+			},
+		}
+	}
+
+	if rest[0] == '<' {
+		if m := xrefRx.FindSubmatchIndex(rest); m != nil {
+			return p.newRef(ast.XRefVariant, string(rest[m[2]:m[3]]), refText(m, 4, 0, 0), x, m[1], lineNum, col), m[1]
+		}
+
+		if m := angleURLRx.FindSubmatchIndex(rest); m != nil {
+			target := rest[m[2]:m[3]]
+			inlines := ast.Inlines{p.newText(target, lineNum, col+x+m[2])}
+			return p.newRef(ast.LinkVariant, string(target), inlines, x, m[1], lineNum, col), m[1]
+		}
+
+		return nil, 0
+	}
+
+	// Links and macros start the word
+	if x > 0 && (isWordByte(text[x-1]) || text[x-1] == ':' || text[x-1] == '/') {
+		return nil, 0
+	}
+
+	if m := xrefMacroRx.FindSubmatchIndex(rest); m != nil {
+		return p.newRef(ast.XRefVariant, string(rest[m[2]:m[3]]), refText(m, 4, 0, 0), x, m[1], lineNum, col), m[1]
+	}
+
+	if m := linkMacroRx.FindSubmatchIndex(rest); m != nil {
+		target := rest[m[4]:m[5]]
+		if string(rest[m[2]:m[3]]) == "mailto" {
+			target = rest[m[2]:m[5]]
+		}
+
+		inlines := refText(m, 6, m[4], m[5])
+		target = p.substituteAttributes(target, lineNum)
+
+		return p.newRef(ast.LinkVariant, string(target), inlines, x, m[1], lineNum, col), m[1]
+	}
+
+	if m := urlRx.FindSubmatchIndex(rest); m != nil {
+		end := m[1]
+		target := rest[:end]
+
+		if m[2] >= 0 {
+			target = rest[:m[2]-1]
+		} else {
+			// Trailing punctuation of the bare URL belongs to the sentence
+			end = len(bytes.TrimRight(target, ".,;:!?)"))
+			target = rest[:end]
+		}
+
+		return p.newRef(ast.LinkVariant, string(target), refText(m, 2, 0, len(target)), x, end, lineNum, col), end
+	}
+
+	return nil, 0
+}
+
+// Reference which takes n bytes of the text starting at x
+func (p *parser) newRef(variant ast.Variant, target string, inlines ast.Inlines, x, n, lineNum, col int) *ast.InlineRef {
+	return &ast.InlineRef{
+		Name:    ast.RefName,
+		Variant: variant,
+		Target:  target,
+		AbstractParentInline: ast.AbstractParentInline{
+			Type:    ast.InlineType,
+			Inlines: inlines,
+			Location: []ast.LocationBoundary{
+				p.at(lineNum, col+x),
+				p.at(lineNum, col+x+n-1),
+			},
+		},
+	}
+}
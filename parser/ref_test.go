@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func ref(variant ast.Variant, target string, loc ast.Location, inlines ...ast.Inline) *ast.InlineRef {
+	return &ast.InlineRef{
+		Name:    ast.RefName,
+		Variant: variant,
+		Target:  target,
+		AbstractParentInline: ast.AbstractParentInline{
+			Type:     ast.InlineType,
+			Inlines:  inlines,
+			Location: loc,
+		},
+	}
+}
+
+func TestParseInlineRefs(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want ast.Inlines
+	}{
+		{
+			name: "Bare URL",
+			text: "See https://example.org.",
+			want: ast.Inlines{
+				text("See ", 1, 1),
+				ref(ast.LinkVariant, "https://example.org", location(1, 5, 1, 23), text("https://example.org", 1, 5)),
+				text(".", 1, 24),
+			},
+		},
+		{
+			name: "URL with text",
+			text: "https://example.org[*Example*]",
+			want: ast.Inlines{
+				ref(ast.LinkVariant, "https://example.org", location(1, 1, 1, 30),
+					span(ast.StrongVariant, ast.ConstrainedForm, location(1, 21, 1, 29), text("Example", 1, 22)),
+				),
+			},
+		},
+		{
+			name: "Angle brackets URL",
+			text: "<ftp://example.org>",
+			want: ast.Inlines{
+				ref(ast.LinkVariant, "ftp://example.org", location(1, 1, 1, 19), text("ftp://example.org", 1, 2)),
+			},
+		},
+		{
+			name: "Link macro",
+			text: "link:docs/index.html[Docs]",
+			want: ast.Inlines{
+				ref(ast.LinkVariant, "docs/index.html", location(1, 1, 1, 26), text("Docs", 1, 22)),
+			},
+		},
+		{
+			name: "Link macro without text",
+			text: "link:index.html[]",
+			want: ast.Inlines{
+				ref(ast.LinkVariant, "index.html", location(1, 1, 1, 17), text("index.html", 1, 6)),
+			},
+		},
+		{
+			name: "Link macro with attribute reference",
+			text: "link:{base}/index.html[]",
+			want: ast.Inlines{
+				ref(ast.LinkVariant, "docs/index.html", location(1, 1, 1, 24),
+					&ast.InlineLiteral{
+						Name:     ast.TextName,
+						Type:     ast.StringType,
+						Value:    "docs/index.html",
+						Location: location(1, 6, 1, 22),
+					},
+				),
+			},
+		},
+		{
+			name: "Mailto",
+			text: "mailto:me@example.org[Mail me]",
+			want: ast.Inlines{
+				ref(ast.LinkVariant, "mailto:me@example.org", location(1, 1, 1, 30), text("Mail me", 1, 23)),
+			},
+		},
+		{
+			name: "Cross reference",
+			text: "<<install>>",
+			want: ast.Inlines{
+				ref(ast.XRefVariant, "install", location(1, 1, 1, 11)),
+			},
+		},
+		{
+			name: "Cross reference with text",
+			text: "<<install,Install it>>",
+			want: ast.Inlines{
+				ref(ast.XRefVariant, "install", location(1, 1, 1, 22), text("Install it", 1, 11)),
+			},
+		},
+		{
+			name: "Xref macro",
+			text: "xref:setup.adoc#install[Install]",
+			want: ast.Inlines{
+				ref(ast.XRefVariant, "setup.adoc#install", location(1, 1, 1, 32), text("Install", 1, 25)),
+			},
+		},
+		{
+			name: "Escaped URL",
+			text: `\https://example.org`,
+			want: ast.Inlines{
+				&ast.InlineLiteral{
+					Name:     ast.TextName,
+					Type:     ast.StringType,
+					Value:    "https://example.org",
+					Location: location(1, 1, 1, 20),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser([]byte(tt.text))
+			p.attributes["base"] = "docs"
+
+			if got := p.parseInlines([]byte(tt.text), 1, 1); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInlines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,745 @@
+package parser
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+// Column specification of the "cols" attribute
+//
+// Pattern: "<multiplier>*<halign>.<valign><width><style>", like "3*", "^.>2a", "~"
+var colSpecRx = regexp.MustCompile(`^(?:(\d+)\*)?([<^>])?(?:\.([<^>]))?(\d+%?|~)?([adehlms])?$`)
+
+// Cell specification placed right before the cell separator
+//
+// Pattern: "<colspan>.<rowspan>+" or "<times>*" followed by "<halign>.<valign><style>"
+var cellSpecRx = regexp.MustCompile(`(?:^|[ \t])(?:(\d+)?(?:\.(\d+))?([*+]))?([<^>])?(?:\.([<^>]))?([adehlms])?$`)
+
+// Cell collected from the table content before its text is parsed
+type tableCell struct {
+	colSpan int
+	rowSpan int
+	repeat  int
+	hAlign  ast.Align
+	vAlign  ast.Align
+	style   ast.Style
+
+	// Column of the cell in the row
+	col int
+
+	// Start of the cell on the document line, not mapped to the source yet
+	start ast.LocationBoundary
+	lines []cellLine
+}
+
+// Part of the cell text placed on the single line
+type cellLine struct {
+	num  int
+	col  int
+	text []byte
+}
+
+// Table content is split into cells, cells are grouped into rows by the number of columns
+//
+// Pattern: "|===" ... "|===" for the prefix-separated values,
+// ",===" for the comma-separated and ":===" for the delimiter-separated ones.
+// "!===" is the table nested into the AsciiDoc cell.
+//
+// Number of columns comes from the "cols" attribute or from the first line of the content.
+// First line followed by the empty line is the header row, unless the "noheader" option is set.
+func (p *parser) parseTable(l *line, meta *blockMeta) *ast.Table {
+	start := p.lineNum
+
+	p.openFence(l)
+
+	for p.nextLine() != nil {
+	}
+
+	last := p.lineNum
+	end := p.lineEnd(last)
+
+	if p.closeFence() {
+		end = p.lineEnd(p.lineNum)
+	} else {
+		p.report(start, "unterminated %s block", ast.TableName)
+	}
+
+	table := &ast.Table{
+		Name:      ast.TableName,
+		Form:      ast.DelimitedForm,
+		Delimiter: string(l.content),
+		AbstructBlock: ast.AbstructBlock{
+			Type: ast.BlockType,
+			Location: []ast.LocationBoundary{
+				p.at(start, 1),
+				end,
+			},
+		},
+	}
+
+	attrs := meta.data.Attributes
+
+	var sep byte
+	table.Variant, sep = tableFormat(l.content[0], attrs["format"])
+
+	if s := attrs["separator"]; s != "" {
+		sep = s[0]
+		if s == `\t` {
+			sep = '\t'
+		}
+	}
+
+	var cells []*tableCell
+	switch table.Variant {
+	case ast.CSVVariant:
+		cells = p.csvCells(start+1, last, sep)
+	case ast.DSVVariant:
+		cells = p.dsvCells(start+1, last, sep)
+	default:
+		cells = p.psvCells(start+1, last, sep)
+	}
+
+	table.Columns = parseColumns(attrs["cols"])
+	if table.Columns == nil && attrs["cols"] != "" {
+		p.report(start, "invalid table columns: %s", attrs["cols"])
+	}
+	if table.Columns == nil {
+		table.Columns = make([]ast.TableColumn, firstLineWidth(cells))
+		for x := range table.Columns {
+			table.Columns[x] = newColumn()
+		}
+	}
+
+	if len(table.Columns) == 0 {
+		return table
+	}
+
+	rows := p.tableRows(cells, len(table.Columns))
+
+	options := meta.data.Options
+
+	header := slices.Contains(options, "header")
+	if !header && !slices.Contains(options, "noheader") {
+		header = start+2 <= last && len(p.lines[start]) > 0 && len(p.lines[start+1]) == 0
+	}
+
+	if header && len(rows) > 0 {
+		table.Head = append(table.Head, p.tableRow(rows[0], table.Columns, true))
+		rows = rows[1:]
+	}
+
+	var foot []*tableCell
+	if slices.Contains(options, "footer") && len(rows) > 0 {
+		foot = rows[len(rows)-1]
+		rows = rows[:len(rows)-1]
+	}
+
+	for _, row := range rows {
+		table.Body = append(table.Body, p.tableRow(row, table.Columns, false))
+	}
+
+	if foot != nil {
+		table.Foot = append(table.Foot, p.tableRow(foot, table.Columns, false))
+	}
+
+	return table
+}
+
+// Variant and the default separator of the table
+func tableFormat(delimiter byte, format string) (ast.Variant, byte) {
+	switch format {
+	case "psv":
+		return ast.PSVVariant, '|'
+	case "csv":
+		return ast.CSVVariant, ','
+	case "tsv":
+		return ast.CSVVariant, '\t'
+	case "dsv":
+		return ast.DSVVariant, ':'
+	}
+
+	switch delimiter {
+	case ',':
+		return ast.CSVVariant, ','
+	case ':':
+		return ast.DSVVariant, ':'
+	}
+
+	return ast.PSVVariant, delimiter
+}
+
+// Limit of the table columns, larger "cols" is invalid
+const maxTableColumns = 1000
+
+// Columns of the "cols" attribute
+//
+// Pattern: "3" for the number of columns or the comma separated specifications like "1,2a,^3"
+//
+// Returns nil for the invalid specification: no columns or more than maxTableColumns.
+func parseColumns(spec string) []ast.TableColumn {
+	if spec == "" {
+		return nil
+	}
+
+	if n, err := strconv.Atoi(spec); err == nil {
+		if n <= 0 || n > maxTableColumns {
+			return nil
+		}
+
+		columns := make([]ast.TableColumn, n)
+		for x := range columns {
+			columns[x] = newColumn()
+		}
+		return columns
+	}
+
+	var columns []ast.TableColumn
+
+	for _, s := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == ';' }) {
+		column := newColumn()
+
+		m := colSpecRx.FindStringSubmatch(strings.TrimSpace(s))
+		if m == nil {
+			columns = append(columns, column)
+			continue
+		}
+
+		if m[2] != "" {
+			column.HAlign = alignOf(m[2][0], false)
+		}
+		if m[3] != "" {
+			column.VAlign = alignOf(m[3][0], true)
+		}
+		if m[4] == "~" {
+			column.Width = 0
+		} else if m[4] != "" {
+			column.Width, _ = strconv.Atoi(strings.TrimSuffix(m[4], "%"))
+		}
+		if m[5] != "" {
+			column.Style = styleOf(m[5][0])
+		}
+
+		repeat := 1
+		if m[1] != "" {
+			repeat, _ = strconv.Atoi(m[1])
+		}
+
+		if repeat > maxTableColumns-len(columns) {
+			return nil
+		}
+
+		for range repeat {
+			columns = append(columns, column)
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil
+	}
+
+	return columns
+}
+
+func newColumn() ast.TableColumn {
+	return ast.TableColumn{
+		Width:  1,
+		HAlign: ast.LeftAlign,
+		VAlign: ast.TopAlign,
+		Style:  ast.DefaultStyle,
+	}
+}
+
+// Cells of the prefix-separated values
+//
+// Cell starts on the separator and takes the text up to the next one,
+// the text may continue on the next lines. Escaped separator "\|" is the part of the text.
+func (p *parser) psvCells(first, last int, sep byte) []*tableCell {
+	var (
+		cells []*tableCell
+		cell  *tableCell
+	)
+
+	for num := first; num <= last; num++ {
+		content := p.lines[num-1]
+		pos := 0
+
+		for {
+			x := separatorIndex(content, pos, sep)
+			if x < 0 {
+				break
+			}
+
+			segment := content[pos:x]
+			m := cellSpecRx.FindSubmatchIndex(segment)
+
+			// Text before the cell spec belongs to the previous cell
+			if cell != nil {
+				text := segment
+				if m != nil {
+					text = segment[:m[0]]
+				}
+				cell.addText(num, pos+1, unescapeSeparator(text, sep))
+				cells = append(cells, cell)
+			}
+
+			cell = p.newTableCell(segment, m, num)
+			cell.start = ast.LocationBoundary{
+				Line:    num,
+				Collumn: x + 1,
+			}
+
+			pos = x + 1
+		}
+
+		if cell != nil {
+			cell.addText(num, pos+1, unescapeSeparator(content[pos:], sep))
+		}
+	}
+
+	if cell != nil {
+		cells = append(cells, cell)
+	}
+
+	return cells
+}
+
+// Cells of the comma-separated values, quoted values may take several lines
+func (p *parser) csvCells(first, last int, sep byte) []*tableCell {
+	if first > last {
+		return nil
+	}
+
+	content := bytes.Join(p.lines[first-1:last], []byte("\n"))
+
+	r := csv.NewReader(bytes.NewReader(content))
+	r.Comma = rune(sep)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	var cells []*tableCell
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			p.report(first+perr.StartLine-1, "invalid csv table row: %s", perr.Err)
+			break
+		}
+
+		for x, field := range record {
+			num, col := r.FieldPos(x)
+			num += first - 1
+
+			if col <= len(p.lines[num-1]) && p.lines[num-1][col-1] == '"' {
+				col++
+			}
+
+			cell := p.newTableCell(nil, nil, num)
+			cell.start = ast.LocationBoundary{
+				Line:    num,
+				Collumn: col,
+			}
+
+			for y, text := range strings.Split(field, "\n") {
+				if y > 0 {
+					col = 1
+				}
+				cell.addText(num+y, col, []byte(text))
+			}
+
+			cells = append(cells, cell)
+		}
+	}
+
+	return cells
+}
+
+// Cells of the delimiter-separated values, each line is the row
+func (p *parser) dsvCells(first, last int, sep byte) []*tableCell {
+	var cells []*tableCell
+
+	for num := first; num <= last; num++ {
+		content := p.lines[num-1]
+
+		if len(content) == 0 {
+			continue
+		}
+
+		pos := 0
+		for {
+			x := separatorIndex(content, pos, sep)
+			if x < 0 {
+				x = len(content)
+			}
+
+			cell := p.newTableCell(nil, nil, num)
+			cell.start = ast.LocationBoundary{
+				Line:    num,
+				Collumn: pos + 1,
+			}
+			cell.addText(num, pos+1, unescapeSeparator(content[pos:x], sep))
+
+			cells = append(cells, cell)
+
+			if x == len(content) {
+				break
+			}
+			pos = x + 1
+		}
+	}
+
+	return cells
+}
+
+// Index of the first separator after pos which is not escaped with the backslash
+func separatorIndex(content []byte, pos int, sep byte) int {
+	for x := pos; x < len(content); x++ {
+		if content[x] == sep && (x == 0 || content[x-1] != '\\') {
+			return x
+		}
+	}
+
+	return -1
+}
+
+func unescapeSeparator(text []byte, sep byte) []byte {
+	return bytes.ReplaceAll(text, []byte{'\\', sep}, []byte{sep})
+}
+
+// Cell with the spec matched by cellSpecRx in the segment of the line lineNum, m is nil for the cell without spec.
+// Spans and the repeat are at most maxTableColumns.
+func (p *parser) newTableCell(segment []byte, m []int, lineNum int) *tableCell {
+	cell := &tableCell{
+		colSpan: 1,
+		rowSpan: 1,
+		repeat:  1,
+	}
+
+	if m == nil {
+		return cell
+	}
+
+	group := func(x int) []byte {
+		if m[2*x] < 0 {
+			return nil
+		}
+		return segment[m[2*x]:m[2*x+1]]
+	}
+
+	// Number of the group x, the one out of 1..maxTableColumns is clamped
+	number := func(x int) int {
+		spec := group(x)
+		if spec == nil {
+			return 1
+		}
+
+		n, _ := strconv.Atoi(string(spec))
+		if n < 1 || n > maxTableColumns {
+			p.report(lineNum, "table cell spec out of range: %s", spec)
+			n = min(max(n, 1), maxTableColumns)
+		}
+
+		return n
+	}
+
+	switch string(group(3)) {
+	case "+":
+		cell.colSpan = number(1)
+		cell.rowSpan = number(2)
+	case "*":
+		cell.repeat = number(1)
+	}
+
+	if a := group(4); a != nil {
+		cell.hAlign = alignOf(a[0], false)
+	}
+	if a := group(5); a != nil {
+		cell.vAlign = alignOf(a[0], true)
+	}
+	if s := group(6); s != nil {
+		cell.style = styleOf(s[0])
+	}
+
+	return cell
+}
+
+func (c *tableCell) addText(num, col int, text []byte) {
+	c.lines = append(c.lines, cellLine{
+		num:  num,
+		col:  col,
+		text: text,
+	})
+}
+
+// Text of the cell without the leading and trailing empty lines and whitespace
+func (c *tableCell) trimmed() []cellLine {
+	lines := slices.Clone(c.lines)
+
+	for len(lines) > 0 && len(bytes.TrimSpace(lines[0].text)) == 0 {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && len(bytes.TrimSpace(lines[len(lines)-1].text)) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	first := &lines[0]
+	text := bytes.TrimLeft(first.text, " \t")
+	first.col += len(first.text) - len(text)
+	first.text = text
+
+	last := &lines[len(lines)-1]
+	last.text = bytes.TrimRight(last.text, " \t")
+
+	return lines
+}
+
+// Number of columns taken by the cells of the first line, at most maxTableColumns
+func firstLineWidth(cells []*tableCell) int {
+	width := 0
+
+	for _, cell := range cells {
+		if cell.start.Line != cells[0].start.Line {
+			break
+		}
+		width += cell.colSpan * cell.repeat
+	}
+
+	return min(width, maxTableColumns)
+}
+
+// Cells grouped into rows
+//
+// Row ends when all its columns are taken by the cells of the row
+// or by the cells of the previous rows spanned down, the row taken by the spans only is empty.
+// Row span of the cell does not run past the last row.
+func (p *parser) tableRows(cells []*tableCell, ncols int) [][]*tableCell {
+	var (
+		rows [][]*tableCell
+		row  []*tableCell
+
+		taken = make([]bool, ncols)
+		// Rows left to the cells spanned down from the previous rows
+		spans = make([]int, ncols)
+
+		col int
+	)
+
+	nextRow := func() {
+		for x := range ncols {
+			taken[x] = spans[x] > 0
+			if spans[x] > 0 {
+				spans[x]--
+			}
+		}
+	}
+
+	nextCol := func() {
+		for col < ncols && taken[col] {
+			col++
+		}
+	}
+
+	nextRow()
+	nextCol()
+
+	for _, cell := range cells {
+		for range cell.repeat {
+			for col >= ncols {
+				rows = append(rows, row)
+				row = nil
+				col = 0
+				nextRow()
+				nextCol()
+			}
+
+			c := *cell
+			c.col = col
+
+			for x := col; x < col+c.colSpan && x < ncols; x++ {
+				taken[x] = true
+				if c.rowSpan > 1 {
+					spans[x] = c.rowSpan - 1
+				}
+			}
+
+			row = append(row, &c)
+			nextCol()
+		}
+	}
+
+	switch {
+	case col >= ncols:
+		rows = append(rows, row)
+	case len(row) > 0:
+		p.report(row[0].start.Line, "dropping cells from incomplete table row")
+	}
+
+	for x, row := range rows {
+		for _, c := range row {
+			c.rowSpan = min(c.rowSpan, len(rows)-x)
+		}
+	}
+
+	return rows
+}
+
+// Row of the parsed cells, cells of the header row do not take the column style
+func (p *parser) tableRow(cells []*tableCell, columns []ast.TableColumn, header bool) ast.TableRow {
+	row := ast.TableRow{}
+
+	for _, c := range cells {
+		column := columns[c.col]
+
+		cell := ast.TableCell{
+			ColSpan: c.colSpan,
+			RowSpan: c.rowSpan,
+			HAlign:  cmp.Or(c.hAlign, column.HAlign),
+			VAlign:  cmp.Or(c.vAlign, column.VAlign),
+			Style:   c.style,
+		}
+
+		if cell.Style == "" {
+			cell.Style = ast.DefaultStyle
+			if !header {
+				cell.Style = column.Style
+			}
+		}
+
+		lines := c.trimmed()
+		start := p.at(c.start.Line, c.start.Collumn)
+		end := start
+
+		if len(lines) > 0 {
+			last := lines[len(lines)-1]
+			end = p.at(last.num, last.col+len(last.text)-1)
+		}
+
+		switch cell.Style {
+		case ast.AsciiDocStyle:
+			cell.Blocks = p.parseCellBlocks(lines)
+		case ast.LiteralStyle:
+			for _, l := range lines {
+				var inlines ast.Inlines
+				if len(l.text) > 0 {
+					inlines = ast.Inlines{p.newText(l.text, l.num, l.col)}
+				}
+				cell.Inlines = append(cell.Inlines, inlines)
+			}
+		default:
+			for _, l := range lines {
+				text := bytes.TrimLeft(l.text, " \t")
+				col := l.col + len(l.text) - len(text)
+				cell.Inlines = append(cell.Inlines, p.parseInlines(bytes.TrimRight(text, " \t"), l.num, col))
+			}
+		}
+
+		cell.Location = []ast.LocationBoundary{start, end}
+
+		row.Cells = append(row.Cells, cell)
+	}
+
+	if len(row.Cells) > 0 {
+		row.Location = []ast.LocationBoundary{
+			row.Cells[0].Location[0],
+			row.Cells[len(row.Cells)-1].Location[1],
+		}
+	}
+
+	return row
+}
+
+// Blocks of the AsciiDoc cell
+//
+// Cell lines are parsed by the separate parser, its lines are numbered from the first cell line
+// and columns start at the cell text on each line. Attributes set in the cell stay in the cell.
+func (p *parser) parseCellBlocks(lines []cellLine) []ast.Block {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	offset := lines[0].num - 1
+	count := lines[len(lines)-1].num - offset
+
+	sub := &parser{
+		name:       p.name,
+		lines:      make([][]byte, count),
+		offset:     p.offset + offset,
+		cols:       make([]int, count),
+		footnotes:  p.footnotes,
+		attributes: maps.Clone(p.attributes),
+		locked:     p.locked,
+		sources:    p.sources,
+		// Lines of the cells are preprocessed already
+		processed: count,
+	}
+
+	for _, l := range lines {
+		sub.lines[l.num-offset-1] = l.text
+		sub.cols[l.num-offset-1] = p.colBase(l.num) + l.col - 1
+	}
+
+	blocks := sub.parseBlocks()
+
+	p.diagnostics = append(p.diagnostics, sub.diagnostics...)
+	p.footnotes = sub.footnotes
+
+	return blocks
+}
+
+func alignOf(b byte, vertical bool) ast.Align {
+	switch b {
+	case '^':
+		if vertical {
+			return ast.MiddleAlign
+		}
+		return ast.CenterAlign
+	case '>':
+		if vertical {
+			return ast.BottomAlign
+		}
+		return ast.RightAlign
+	}
+
+	if vertical {
+		return ast.TopAlign
+	}
+	return ast.LeftAlign
+}
+
+func styleOf(b byte) ast.Style {
+	switch b {
+	case 'a':
+		return ast.AsciiDocStyle
+	case 'e':
+		return ast.EmphasisStyle
+	case 'h':
+		return ast.HeaderStyle
+	case 'l':
+		return ast.LiteralStyle
+	case 'm':
+		return ast.MonospaceStyle
+	case 's':
+		return ast.StrongStyle
+	}
+
+	return ast.DefaultStyle
+}
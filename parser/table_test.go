@@ -0,0 +1,501 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mynameisglebushka/parser-prosto-adoc/ast"
+)
+
+func TestParseTables(t *testing.T) {
+
+	const (
+		ImplicitHeader = "Implicit header"
+		Columns        = "Columns"
+		HeaderFooter   = "Header and footer"
+		CSV            = "CSV"
+		DSV            = "DSV"
+		Unterminated   = "Unterminated"
+		InvalidColumns = "Invalid columns"
+		RowSpans       = "Row spans"
+		HugeSpan       = "Huge span"
+		HugeRepeat     = "Huge repeat"
+	)
+
+	cases := map[string][]string{
+		ImplicitHeader: {
+			"|===",
+			"|Name |Value",
+			"",
+			"|a |1",
+			"|b |2",
+			"|===",
+		},
+		Columns: {
+			`[cols="1,2a"]`,
+			"|===",
+			"|One",
+			"|* item",
+			"2+^|Spanned",
+			"|===",
+		},
+		HeaderFooter: {
+			"[%header%footer,cols=2]",
+			"|===",
+			".2+|A |B",
+			"|C",
+			"|D |E",
+			"|===",
+		},
+		CSV: {
+			",===",
+			`a,"b, c"`,
+			"d,e",
+			",===",
+		},
+		DSV: {
+			":===",
+			"a:b",
+			":===",
+		},
+		Unterminated: {
+			"|===",
+			"|a |b",
+			"|c",
+		},
+		RowSpans: {
+			"|===",
+			".2+|z",
+			".2+|z",
+			"|===",
+		},
+		InvalidColumns: {
+			"[cols=-1]",
+			"|===",
+			"|a |b",
+			"|===",
+		},
+		HugeSpan: {
+			"|===",
+			"99999999999999+|a",
+			"|===",
+		},
+		HugeRepeat: {
+			"[cols=2]",
+			"|===",
+			"900000000*|a",
+			"|===",
+		},
+	}
+
+	column := func(width int, style ast.Style) ast.TableColumn {
+		return ast.TableColumn{
+			Width:  width,
+			HAlign: ast.LeftAlign,
+			VAlign: ast.TopAlign,
+			Style:  style,
+		}
+	}
+
+	cell := func(loc ast.Location, lines ...ast.Inlines) ast.TableCell {
+		return ast.TableCell{
+			ColSpan:  1,
+			RowSpan:  1,
+			HAlign:   ast.LeftAlign,
+			VAlign:   ast.TopAlign,
+			Style:    ast.DefaultStyle,
+			Inlines:  lines,
+			Location: loc,
+		}
+	}
+
+	row := func(cells ...ast.TableCell) ast.TableRow {
+		return ast.TableRow{
+			Cells: cells,
+			Location: []ast.LocationBoundary{
+				cells[0].Location[0],
+				cells[len(cells)-1].Location[1],
+			},
+		}
+	}
+
+	table := func(delimiter string, variant ast.Variant, loc ast.Location, columns []ast.TableColumn, head, body, foot []ast.TableRow) *ast.Table {
+		return &ast.Table{
+			Name:      ast.TableName,
+			Form:      ast.DelimitedForm,
+			Variant:   variant,
+			Delimiter: delimiter,
+			Columns:   columns,
+			Head:      head,
+			Body:      body,
+			Foot:      foot,
+			AbstructBlock: ast.AbstructBlock{
+				Type:     ast.BlockType,
+				Location: loc,
+			},
+		}
+	}
+
+	twoColumns := []ast.TableColumn{
+		column(1, ast.DefaultStyle),
+		column(1, ast.DefaultStyle),
+	}
+
+	spanned := cell(location(5, 4, 5, 11), ast.Inlines{text("Spanned", 5, 5)})
+	spanned.ColSpan = 2
+	spanned.HAlign = ast.CenterAlign
+
+	item := cell(location(4, 1, 4, 7))
+	item.Style = ast.AsciiDocStyle
+	item.Inlines = nil
+	item.Blocks = []ast.Block{
+		list("*", ast.UnorderedVariant, location(4, 2, 4, 7),
+			listItem("*", location(4, 2, 4, 7), ast.Inlines{text("item", 4, 4)}),
+		),
+	}
+
+	columns := table("|===", ast.PSVVariant, location(2, 1, 6, 4),
+		[]ast.TableColumn{
+			column(1, ast.DefaultStyle),
+			column(2, ast.AsciiDocStyle),
+		},
+		nil,
+		[]ast.TableRow{
+			row(cell(location(3, 1, 3, 4), ast.Inlines{text("One", 3, 2)}), item),
+			row(spanned),
+		},
+		nil,
+	)
+	columns.MetaData = ast.BlockMetaData{
+		Attributes: map[string]string{
+			"cols": "1,2a",
+		},
+		Location: location(1, 1, 1, 13),
+	}
+
+	rowSpanned := cell(location(3, 4, 3, 5), ast.Inlines{text("A", 3, 5)})
+	rowSpanned.RowSpan = 2
+
+	headerFooter := table("|===", ast.PSVVariant, location(2, 1, 6, 4), twoColumns,
+		[]ast.TableRow{
+			row(rowSpanned, cell(location(3, 7, 3, 8), ast.Inlines{text("B", 3, 8)})),
+		},
+		[]ast.TableRow{
+			row(cell(location(4, 1, 4, 2), ast.Inlines{text("C", 4, 2)})),
+		},
+		[]ast.TableRow{
+			row(
+				cell(location(5, 1, 5, 2), ast.Inlines{text("D", 5, 2)}),
+				cell(location(5, 4, 5, 5), ast.Inlines{text("E", 5, 5)}),
+			),
+		},
+	)
+	headerFooter.MetaData = ast.BlockMetaData{
+		Attributes: map[string]string{
+			"cols": "2",
+		},
+		Options:  []string{"header", "footer"},
+		Location: location(1, 1, 1, 23),
+	}
+
+	invalidColumns := table("|===", ast.PSVVariant, location(2, 1, 4, 4), twoColumns,
+		nil,
+		[]ast.TableRow{
+			row(
+				cell(location(3, 1, 3, 2), ast.Inlines{text("a", 3, 2)}),
+				cell(location(3, 4, 3, 5), ast.Inlines{text("b", 3, 5)}),
+			),
+		},
+		nil,
+	)
+	invalidColumns.MetaData = ast.BlockMetaData{
+		Attributes: map[string]string{
+			"cols": "-1",
+		},
+		Location: location(1, 1, 1, 9),
+	}
+
+	// Second row is taken by the span, span of the last cell is clamped to the last row
+	firstSpan := cell(location(2, 4, 2, 5), ast.Inlines{text("z", 2, 5)})
+	firstSpan.RowSpan = 2
+
+	rowSpans := table("|===", ast.PSVVariant, location(1, 1, 4, 4),
+		[]ast.TableColumn{column(1, ast.DefaultStyle)},
+		nil,
+		[]ast.TableRow{
+			row(firstSpan),
+			{},
+			row(cell(location(3, 4, 3, 5), ast.Inlines{text("z", 3, 5)})),
+		},
+		nil,
+	)
+
+	// Span and repeat are clamped to maxTableColumns
+	hugeSpanned := cell(location(2, 16, 2, 17), ast.Inlines{text("a", 2, 17)})
+	hugeSpanned.ColSpan = maxTableColumns
+
+	hugeColumns := make([]ast.TableColumn, maxTableColumns)
+	for x := range hugeColumns {
+		hugeColumns[x] = column(1, ast.DefaultStyle)
+	}
+
+	hugeSpan := table("|===", ast.PSVVariant, location(1, 1, 3, 4), hugeColumns,
+		nil,
+		[]ast.TableRow{row(hugeSpanned)},
+		nil,
+	)
+
+	repeated := cell(location(3, 11, 3, 12), ast.Inlines{text("a", 3, 12)})
+
+	var repeatedRows []ast.TableRow
+	for range maxTableColumns / 2 {
+		repeatedRows = append(repeatedRows, row(repeated, repeated))
+	}
+
+	hugeRepeat := table("|===", ast.PSVVariant, location(2, 1, 4, 4), twoColumns,
+		nil,
+		repeatedRows,
+		nil,
+	)
+	hugeRepeat.MetaData = ast.BlockMetaData{
+		Attributes: map[string]string{
+			"cols": "2",
+		},
+		Location: location(1, 1, 1, 8),
+	}
+
+	tests := []struct {
+		name        string
+		input       []byte
+		want        []ast.Block
+		diagnostics []Diagnostic
+	}{
+		{
+			name:  ImplicitHeader,
+			input: []byte(strings.Join(cases[ImplicitHeader], "\n")),
+			want: []ast.Block{
+				table("|===", ast.PSVVariant, location(1, 1, 6, 4), twoColumns,
+					[]ast.TableRow{
+						row(
+							cell(location(2, 1, 2, 5), ast.Inlines{text("Name", 2, 2)}),
+							cell(location(2, 7, 2, 12), ast.Inlines{text("Value", 2, 8)}),
+						),
+					},
+					[]ast.TableRow{
+						row(
+							cell(location(4, 1, 4, 2), ast.Inlines{text("a", 4, 2)}),
+							cell(location(4, 4, 4, 5), ast.Inlines{text("1", 4, 5)}),
+						),
+						row(
+							cell(location(5, 1, 5, 2), ast.Inlines{text("b", 5, 2)}),
+							cell(location(5, 4, 5, 5), ast.Inlines{text("2", 5, 5)}),
+						),
+					},
+					nil,
+				),
+			},
+		},
+		{
+			name:  Columns,
+			input: []byte(strings.Join(cases[Columns], "\n")),
+			want:  []ast.Block{columns},
+		},
+		{
+			name:  HeaderFooter,
+			input: []byte(strings.Join(cases[HeaderFooter], "\n")),
+			want:  []ast.Block{headerFooter},
+		},
+		{
+			name:  CSV,
+			input: []byte(strings.Join(cases[CSV], "\n")),
+			want: []ast.Block{
+				table(",===", ast.CSVVariant, location(1, 1, 4, 4), twoColumns,
+					nil,
+					[]ast.TableRow{
+						row(
+							cell(location(2, 1, 2, 1), ast.Inlines{text("a", 2, 1)}),
+							cell(location(2, 4, 2, 7), ast.Inlines{text("b, c", 2, 4)}),
+						),
+						row(
+							cell(location(3, 1, 3, 1), ast.Inlines{text("d", 3, 1)}),
+							cell(location(3, 3, 3, 3), ast.Inlines{text("e", 3, 3)}),
+						),
+					},
+					nil,
+				),
+			},
+		},
+		{
+			name:  DSV,
+			input: []byte(strings.Join(cases[DSV], "\n")),
+			want: []ast.Block{
+				table(":===", ast.DSVVariant, location(1, 1, 3, 4), twoColumns,
+					nil,
+					[]ast.TableRow{
+						row(
+							cell(location(2, 1, 2, 1), ast.Inlines{text("a", 2, 1)}),
+							cell(location(2, 3, 2, 3), ast.Inlines{text("b", 2, 3)}),
+						),
+					},
+					nil,
+				),
+			},
+		},
+		{
+			name:  Unterminated,
+			input: []byte(strings.Join(cases[Unterminated], "\n")),
+			want: []ast.Block{
+				table("|===", ast.PSVVariant, location(1, 1, 3, 2), twoColumns,
+					nil,
+					[]ast.TableRow{
+						row(
+							cell(location(2, 1, 2, 2), ast.Inlines{text("a", 2, 2)}),
+							cell(location(2, 4, 2, 5), ast.Inlines{text("b", 2, 5)}),
+						),
+					},
+					nil,
+				),
+			},
+			diagnostics: []Diagnostic{
+				{
+					Line:    1,
+					Message: "unterminated table block",
+				},
+				{
+					Line:    3,
+					Message: "dropping cells from incomplete table row",
+				},
+			},
+		},
+		{
+			name:  RowSpans,
+			input: []byte(strings.Join(cases[RowSpans], "\n")),
+			want:  []ast.Block{rowSpans},
+		},
+		{
+			name:  InvalidColumns,
+			input: []byte(strings.Join(cases[InvalidColumns], "\n")),
+			want:  []ast.Block{invalidColumns},
+			diagnostics: []Diagnostic{
+				{
+					Line:    2,
+					Message: "invalid table columns: -1",
+				},
+			},
+		},
+		{
+			name:  HugeSpan,
+			input: []byte(strings.Join(cases[HugeSpan], "\n")),
+			want:  []ast.Block{hugeSpan},
+			diagnostics: []Diagnostic{
+				{
+					Line:    2,
+					Message: "table cell spec out of range: 99999999999999",
+				},
+			},
+		},
+		{
+			name:  HugeRepeat,
+			input: []byte(strings.Join(cases[HugeRepeat], "\n")),
+			want:  []ast.Block{hugeRepeat},
+			diagnostics: []Diagnostic{
+				{
+					Line:    3,
+					Message: "table cell spec out of range: 900000000",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParser(tt.input)
+
+			doc := p.parseDocument()
+
+			if !reflect.DeepEqual(doc.Blocks, tt.want) {
+				t.Errorf("parseDocument().Blocks = %v, want %v", doc.Blocks, tt.want)
+			}
+
+			if !reflect.DeepEqual(p.diagnostics, tt.diagnostics) {
+				t.Errorf("parseDocument() diagnostics = %v, want %v", p.diagnostics, tt.diagnostics)
+			}
+		})
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	column := func(width int, halign, valign ast.Align, style ast.Style) ast.TableColumn {
+		return ast.TableColumn{
+			Width:  width,
+			HAlign: halign,
+			VAlign: valign,
+			Style:  style,
+		}
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		want []ast.TableColumn
+	}{
+		{
+			name: "Empty",
+			spec: "",
+			want: nil,
+		},
+		{
+			name: "Count",
+			spec: "2",
+			want: []ast.TableColumn{
+				column(1, ast.LeftAlign, ast.TopAlign, ast.DefaultStyle),
+				column(1, ast.LeftAlign, ast.TopAlign, ast.DefaultStyle),
+			},
+		},
+		{
+			name: "Multiplier",
+			spec: "2*m",
+			want: []ast.TableColumn{
+				column(1, ast.LeftAlign, ast.TopAlign, ast.MonospaceStyle),
+				column(1, ast.LeftAlign, ast.TopAlign, ast.MonospaceStyle),
+			},
+		},
+		{
+			name: "Negative count",
+			spec: "-1",
+			want: nil,
+		},
+		{
+			name: "Too many columns",
+			spec: "1001",
+			want: nil,
+		},
+		{
+			name: "Too large multiplier",
+			spec: "1,1000000000*",
+			want: nil,
+		},
+		{
+			name: "Zero multiplier",
+			spec: "0*",
+			want: nil,
+		},
+		{
+			name: "Alignment and width",
+			spec: "^.>30%h, ~",
+			want: []ast.TableColumn{
+				column(30, ast.CenterAlign, ast.BottomAlign, ast.HeaderStyle),
+				column(0, ast.LeftAlign, ast.TopAlign, ast.DefaultStyle),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseColumns(tt.spec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}